@@ -0,0 +1,86 @@
+package benchmark
+
+import (
+	"errors"
+	"math"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"route-planning/shortestpath"
+	"time"
+)
+
+// ErrNoOneToAll is returned by Run for single-source queries and algorithms
+// which do not implement shortestpath.OneToAll.
+var ErrNoOneToAll = errors.New("the algorithm cannot answer single-source queries")
+
+// Run answers all queries with algo on g. Single-source queries use the
+// algorithm's ToAll method and require algo to implement
+// shortestpath.OneToAll, as answering them with a Pair query per node would
+// take quadratic time.
+func Run(g graph.Graph, algo shortestpath.Algorithm, queries graphio.QuerySet) (graphio.QueryResults, error) {
+	ota, ok := algo.(shortestpath.OneToAll)
+	if !ok && queries.Kind == graphio.SingleSource {
+		return graphio.QueryResults{}, ErrNoOneToAll
+	}
+
+	res := graphio.QueryResults{
+		Kind:    queries.Kind,
+		Nodes:   g.N(),
+		Results: make([]graphio.QueryResult, len(queries.Queries)),
+	}
+
+	res.Edges, res.MinCost, res.MaxCost = arcStatistics(g)
+
+	var total time.Duration
+	for i, q := range queries.Queries {
+		start := time.Now()
+
+		var cost float64
+		if queries.Kind == graphio.PointToPoint {
+			cost, _ = algo.Pair(q.Source, q.Target)
+		} else {
+			dist, _ := ota.ToAll(q.Source)
+			cost = checksum(dist)
+		}
+
+		total += time.Since(start)
+
+		res.Results[i] = graphio.QueryResult{Query: q, Cost: cost}
+	}
+
+	if len(queries.Queries) > 0 {
+		res.AverageTime = total / time.Duration(len(queries.Queries))
+	}
+
+	return res, nil
+}
+
+func checksum(cost []float64) float64 {
+	sum := 0.0
+	for _, c := range cost {
+		if !math.IsInf(c, 0) {
+			sum += c
+		}
+	}
+
+	return sum
+}
+
+func arcStatistics(g graph.Graph) (int, float64, float64) {
+	m := 0
+	min, max := math.Inf(0), math.Inf(-1)
+
+	for i := 0; i < g.N(); i++ {
+		for _, e := range g.OutgoingEdges(graph.Node(i)) {
+			m++
+			min = math.Min(min, e.Cost)
+			max = math.Max(max, e.Cost)
+		}
+	}
+
+	if m == 0 {
+		return 0, 0, 0
+	}
+
+	return m, min, max
+}
@@ -0,0 +1,68 @@
+package benchmark_test
+
+import (
+	"errors"
+	"math"
+	"route-planning/benchmark"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"route-planning/shortestpath"
+	"testing"
+)
+
+var testGraph = graph.NewAdjacencyList([]graph.Edge{
+	{From: 0, To: 1, Cost: 2},
+	{From: 1, To: 2, Cost: 3},
+	{From: 0, To: 2, Cost: 7},
+}, 4)
+
+func TestRunPointToPoint(t *testing.T) {
+	queries := graphio.QuerySet{
+		Kind:    graphio.PointToPoint,
+		Queries: []graphio.Query{{Source: 0, Target: 2}, {Source: 2, Target: 0}},
+	}
+
+	res, err := benchmark.Run(testGraph, &shortestpath.BidirectDijkstra{
+		ForwardGraph:  testGraph,
+		BackwardGraph: testGraph.Reverted(),
+	}, queries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Edges != 3 || res.MinCost != 2 || res.MaxCost != 7 {
+		t.Errorf("expected 3 edges with costs in [2, 7], got %d in [%f, %f]", res.Edges, res.MinCost, res.MaxCost)
+	}
+
+	if c := res.Results[0].Cost; c != 5 {
+		t.Errorf("query 0: expected 5, got %f", c)
+	}
+
+	if c := res.Results[1].Cost; !math.IsInf(c, 0) {
+		t.Errorf("query 1: expected inf, got %f", c)
+	}
+}
+
+func TestRunSingleSource(t *testing.T) {
+	queries := graphio.QuerySet{
+		Kind:    graphio.SingleSource,
+		Queries: []graphio.Query{{Source: 0}, {Source: 1}},
+	}
+
+	res, err := benchmark.Run(testGraph, shortestpath.Dijkstra{Graph: testGraph}, queries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, expected := range []float64{7, 3} {
+		if c := res.Results[i].Cost; c != expected {
+			t.Errorf("query %d: expected checksum %f, got %f", i, expected, c)
+		}
+	}
+
+	// Pair-only algorithms cannot answer single-source queries.
+	bidirect := &shortestpath.BidirectDijkstra{ForwardGraph: testGraph, BackwardGraph: testGraph.Reverted()}
+	if _, err := benchmark.Run(testGraph, bidirect, queries); !errors.Is(err, benchmark.ErrNoOneToAll) {
+		t.Errorf("expected ErrNoOneToAll for a pair-only algorithm, got %v", err)
+	}
+}
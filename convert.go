@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"route-planning/shortestpath"
+	"strconv"
+	"strings"
+)
+
+type ConvertCommand struct {
+	To     string `short:"t" long:"to" description:"the output format" choice:"dimacs" choice:"mtx" choice:"metis" choice:"geojson" choice:"dot" choice:"graphml" required:"true"`
+	Output string `short:"o" long:"output" description:"output file, defaults to stdout"`
+
+	BoundingBox string  `long:"bbox" description:"only keep nodes within 'minLat,minLon,maxLat,maxLon'"`
+	Center      int     `long:"center" description:"only keep nodes within --radius of this node"`
+	Radius      float64 `long:"radius" description:"network distance from --center"`
+
+	Round bool `long:"round" description:"round costs to integers, e.g. for DIMACS output"`
+
+	FileArg FileArg `positional-args:"true" required:"true"`
+}
+
+func runConvert(cmd ConvertCommand) {
+	if cmd.BoundingBox != "" && (cmd.Center != 0 || cmd.Radius != 0) {
+		fmt.Println("--bbox cannot be combined with --center and --radius")
+		os.Exit(1)
+	}
+
+	// Keep stdout clean if the graph is written to it.
+	log := io.Writer(os.Stdout)
+	if cmd.Output == "" {
+		log = os.Stderr
+	}
+
+	g, in := loadGraph(cmd.FileArg.File, log)
+
+	var coords []graph.Coordinate
+	if cmd.To == "geojson" || cmd.BoundingBox != "" {
+		coords = loadCoordinates(in, g.N())
+	}
+
+	var sub *graph.Subgraph
+	switch {
+	case cmd.BoundingBox != "":
+		box, err := parseBoundingBox(cmd.BoundingBox)
+		if err != nil {
+			fmt.Printf("Error parsing bounding box: %v\n", err)
+			os.Exit(1)
+		}
+		s := graph.BoundingBoxSubgraph(g, coords, box)
+		sub = &s
+	case cmd.Center > 0:
+		s, err := shortestpath.RadiusSubgraph(g, graph.Node(cmd.Center-1), cmd.Radius)
+		if err != nil {
+			fmt.Printf("Error extracting subgraph: %v\n", err)
+			os.Exit(1)
+		}
+		sub = &s
+	}
+
+	if sub != nil {
+		g = sub.Graph
+		if coords != nil {
+			coords = sub.Coordinates(coords)
+		}
+		fmt.Fprintf(log, "Extracted subgraph with %d nodes\n", g.N())
+	}
+
+	if cmd.Round {
+		g = roundCosts(g)
+	}
+
+	var out graphio.GraphWriter
+	switch cmd.To {
+	case "dimacs":
+		out = graphio.NewDIMACSOutput()
+	case "mtx":
+		out = graphio.NewMTXOutput()
+	case "metis":
+		out = graphio.NewMETISOutput(vertexWeights(in, sub))
+	case "geojson":
+		out = graphio.NewGeoJSONOutput(coords)
+	case "dot":
+		out = graphio.NewDOTOutput(nil)
+	case "graphml":
+		out = graphio.NewGraphMLOutput(nil)
+	}
+
+	write := func(w io.Writer) error {
+		return out.WriteGraph(w, g)
+	}
+
+	if cmd.Output == "" {
+		if err := write(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing graph: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	writeFile(cmd.Output, write)
+}
+
+// vertexWeights returns the vertex weights provided by in for the nodes of
+// sub or of the whole graph if sub is nil.
+func vertexWeights(in graphio.GraphInput, sub *graph.Subgraph) [][]int64 {
+	mi, ok := in.(graphio.METISInput)
+	if !ok {
+		return nil
+	}
+
+	weights, err := mi.LoadVertexWeights()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading vertex weights: %v\n", err)
+		os.Exit(1)
+	}
+
+	if weights == nil || sub == nil {
+		return weights
+	}
+
+	subWeights := make([][]int64, len(sub.Original))
+	for i, v := range sub.Original {
+		subWeights[i] = weights[v]
+	}
+	return subWeights
+}
+
+// roundCosts returns g with all costs rounded to the nearest integer.
+func roundCosts(g graph.Graph) graph.Graph {
+	var edges []graph.Edge
+	for v := 0; v < g.N(); v++ {
+		for _, e := range g.OutgoingEdges(graph.Node(v)) {
+			e.Cost = math.Round(e.Cost)
+			edges = append(edges, e)
+		}
+	}
+
+	rounded, err := graph.NewCSR(edges, g.N())
+	if err != nil {
+		fmt.Printf("Error rounding costs: %v\n", err)
+		os.Exit(1)
+	}
+	return rounded
+}
+
+// parseBoundingBox parses a bounding box of the form
+// 'minLat,minLon,maxLat,maxLon'.
+func parseBoundingBox(s string) (graph.BoundingBox, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) != 4 {
+		return graph.BoundingBox{}, fmt.Errorf("expected 'minLat,minLon,maxLat,maxLon' but got %q", s)
+	}
+
+	var values [4]float64
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return graph.BoundingBox{}, err
+		}
+		values[i] = v
+	}
+
+	return graph.BoundingBox{
+		Min: graph.Coordinate{Lat: values[0], Lon: values[1]},
+		Max: graph.Coordinate{Lat: values[2], Lon: values[3]},
+	}, nil
+}
@@ -1,5 +1,7 @@
 package graph
 
+import "unsafe"
+
 type listEntry struct {
 	node Node
 	cost float64
@@ -55,3 +57,12 @@ func (l adjacencyList) Reverted() Graph {
 
 	return reverted
 }
+
+func (l adjacencyList) MemoryFootprint() int {
+	size := int(unsafe.Sizeof(l)) + cap(l)*int(unsafe.Sizeof(l[0]))
+	for _, neighbours := range l {
+		size += cap(neighbours) * int(unsafe.Sizeof(listEntry{}))
+	}
+
+	return size
+}
@@ -0,0 +1,36 @@
+package graph
+
+import "math"
+
+// Coordinate is the geographic position of a node in degrees.
+type Coordinate struct {
+	Lat float64
+	Lon float64
+}
+
+// BoundingBox is the rectangle spanned by the south-west corner Min and the
+// north-east corner Max.
+type BoundingBox struct {
+	Min Coordinate
+	Max Coordinate
+}
+
+func (b BoundingBox) Contains(c Coordinate) bool {
+	return c.Lat >= b.Min.Lat && c.Lat <= b.Max.Lat &&
+		c.Lon >= b.Min.Lon && c.Lon <= b.Max.Lon
+}
+
+// earthRadius is the mean earth radius in meters.
+const earthRadius = 6371008.8
+
+// Distance returns the great-circle distance between c and o in meters.
+func (c Coordinate) Distance(o Coordinate) float64 {
+	lat1, lat2 := c.Lat*math.Pi/180, o.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (o.Lon - c.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
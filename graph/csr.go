@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// CSR is a graph in compressed sparse row format. The edges leaving v are
+// stored at the indices Offsets[v] to Offsets[v+1] of Targets and Costs. The
+// arrays are exported to allow storing them without conversion.
+type CSR struct {
+	Offsets []uint64
+	Targets []uint32
+	Costs   []float64
+}
+
+// NewCSR returns the graph with n nodes and edges in CSR format. Edges leaving
+// the same node keep their order. The targets are stored as uint32, more nodes
+// are an error.
+func NewCSR(edges []Edge, n int) (*CSR, error) {
+	if err := checkCSRSize(n); err != nil {
+		return nil, err
+	}
+	return newCSR(edges, n), nil
+}
+
+// checkCSRSize returns an error if n nodes cannot be stored in a CSR graph.
+func checkCSRSize(n int) error {
+	if n > maxCompactSize {
+		return fmt.Errorf("CSR graph with %d nodes exceeds the limit of %d", n, uint64(maxCompactSize))
+	}
+	return nil
+}
+
+// newCSR is NewCSR for n known to fit.
+func newCSR(edges []Edge, n int) *CSR {
+	g := &CSR{
+		Offsets: make([]uint64, n+1),
+		Targets: make([]uint32, len(edges)),
+		Costs:   make([]float64, len(edges)),
+	}
+
+	for _, e := range edges {
+		g.Offsets[e.From+1]++
+	}
+
+	for v := 0; v < n; v++ {
+		g.Offsets[v+1] += g.Offsets[v]
+	}
+
+	next := make([]uint64, n)
+	copy(next, g.Offsets[:n])
+
+	for _, e := range edges {
+		i := next[e.From]
+		g.Targets[i] = uint32(e.To)
+		g.Costs[i] = e.Cost
+		next[e.From]++
+	}
+
+	return g
+}
+
+// Edges returns all edges of g ordered by their source node.
+func (g *CSR) Edges() []Edge {
+	edges := make([]Edge, 0, len(g.Targets))
+	for v := 0; v < g.N(); v++ {
+		edges = append(edges, g.OutgoingEdges(Node(v))...)
+	}
+
+	return edges
+}
+
+func (g *CSR) OutgoingEdges(v Node) []Edge {
+	start, end := g.Offsets[v], g.Offsets[v+1]
+	edges := make([]Edge, end-start)
+	for i := range edges {
+		edges[i] = Edge{
+			From: v,
+			To:   Node(g.Targets[start+uint64(i)]),
+			Cost: g.Costs[start+uint64(i)],
+		}
+	}
+
+	return edges
+}
+
+func (g *CSR) N() int {
+	return len(g.Offsets) - 1
+}
+
+func (g *CSR) Reverted() Graph {
+	reverted := make([]Edge, 0, len(g.Targets))
+	for _, e := range g.Edges() {
+		reverted = append(reverted, e.Reverted())
+	}
+
+	return newCSR(reverted, g.N())
+}
+
+func (g *CSR) MemoryFootprint() int {
+	return int(unsafe.Sizeof(*g)) +
+		cap(g.Offsets)*int(unsafe.Sizeof(uint64(0))) +
+		cap(g.Targets)*int(unsafe.Sizeof(uint32(0))) +
+		cap(g.Costs)*int(unsafe.Sizeof(float64(0)))
+}
+
+// CSRBuilder builds a CSR graph from edges provided twice: first to count the
+// edges leaving every node and then to fill the arrays. Unlike NewCSR it
+// never holds a list of all edges.
+type CSRBuilder struct {
+	degrees []uint64
+	g       *CSR
+	// next is the index of the next edge of every node. It reuses degrees.
+	next []uint64
+}
+
+// NewCSRBuilder returns a builder for a graph with n nodes. Like for NewCSR,
+// more nodes than fit into uint32 are an error.
+func NewCSRBuilder(n int) (*CSRBuilder, error) {
+	if err := checkCSRSize(n); err != nil {
+		return nil, err
+	}
+	return &CSRBuilder{degrees: make([]uint64, n)}, nil
+}
+
+// Count counts an edge leaving from in the first pass.
+func (b *CSRBuilder) Count(from Node) error {
+	if from < 0 || int(from) >= len(b.degrees) {
+		return fmt.Errorf("source node %d out of range [0, %d)", from, len(b.degrees))
+	}
+
+	b.degrees[from]++
+	return nil
+}
+
+// Allocate ends the first pass and allocates the graph.
+func (b *CSRBuilder) Allocate() {
+	n := len(b.degrees)
+
+	b.g = &CSR{
+		Offsets: make([]uint64, n+1),
+	}
+
+	for v, degree := range b.degrees {
+		b.g.Offsets[v+1] = b.g.Offsets[v] + degree
+	}
+
+	m := b.g.Offsets[n]
+	b.g.Targets = make([]uint32, m)
+	b.g.Costs = make([]float64, m)
+
+	b.next = b.degrees
+	copy(b.next, b.g.Offsets[:n])
+	b.degrees = nil
+}
+
+// Add stores an edge in the second pass. Edges must be added in the same
+// order as they were counted.
+func (b *CSRBuilder) Add(e Edge) error {
+	if e.From < 0 || int(e.From) >= b.g.N() {
+		return fmt.Errorf("source node %d out of range [0, %d)", e.From, b.g.N())
+	}
+
+	i := b.next[e.From]
+	if i == b.g.Offsets[e.From+1] {
+		return fmt.Errorf("node %d has more edges than counted", e.From)
+	}
+
+	b.g.Targets[i] = uint32(e.To)
+	b.g.Costs[i] = e.Cost
+	b.next[e.From]++
+
+	return nil
+}
+
+// Build ends the second pass and returns the graph.
+func (b *CSRBuilder) Build() (*CSR, error) {
+	for v, i := range b.next {
+		if i != b.g.Offsets[v+1] {
+			return nil, fmt.Errorf("node %d has fewer edges than counted", v)
+		}
+	}
+
+	g := b.g
+	*b = CSRBuilder{}
+	return g, nil
+}
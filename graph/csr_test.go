@@ -0,0 +1,109 @@
+package graph_test
+
+import (
+	"math"
+	"reflect"
+	"route-planning/graph"
+	"testing"
+)
+
+func TestNewCSR(t *testing.T) {
+	edges := []graph.Edge{
+		{From: 2, To: 0, Cost: 4},
+		{From: 0, To: 1, Cost: 1},
+		{From: 0, To: 2, Cost: 2},
+	}
+
+	sut, err := graph.NewCSR(edges, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sut.N() != 4 {
+		t.Fatalf("expected 4 nodes, got %d", sut.N())
+	}
+
+	expected := [][]graph.Edge{
+		{{From: 0, To: 1, Cost: 1}, {From: 0, To: 2, Cost: 2}},
+		{},
+		{{From: 2, To: 0, Cost: 4}},
+		{},
+	}
+
+	for i, want := range expected {
+		if got := sut.OutgoingEdges(graph.Node(i)); !reflect.DeepEqual(got, want) {
+			t.Errorf("outgoing edges of %d: expected %v, got %v", i, want, got)
+		}
+	}
+
+	reverted := sut.Reverted()
+	if got, want := reverted.OutgoingEdges(0), []graph.Edge{{From: 0, To: 2, Cost: 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reverted outgoing edges of 0: expected %v, got %v", want, got)
+	}
+	if got, want := reverted.OutgoingEdges(2), []graph.Edge{{From: 2, To: 0, Cost: 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reverted outgoing edges of 2: expected %v, got %v", want, got)
+	}
+}
+
+func TestCSRBuilder(t *testing.T) {
+	edges := []graph.Edge{
+		{From: 2, To: 0, Cost: 4},
+		{From: 0, To: 1, Cost: 1},
+		{From: 0, To: 2, Cost: 2},
+	}
+
+	b, err := graph.NewCSRBuilder(4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, e := range edges {
+		if err := b.Count(e.From); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	b.Allocate()
+	for _, e := range edges {
+		if err := b.Add(e); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	g, err := b.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected, _ := graph.NewCSR(edges, 4); !reflect.DeepEqual(g, expected) {
+		t.Errorf("expected %v, got %v", expected, g)
+	}
+}
+
+func TestCSRBuilderMismatch(t *testing.T) {
+	b, err := graph.NewCSRBuilder(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := b.Count(2); err == nil {
+		t.Error("expected an error counting an edge of a node out of range")
+	}
+	b.Count(0)
+	b.Allocate()
+
+	if err := b.Add(graph.Edge{From: 1, To: 0}); err == nil {
+		t.Error("expected an error adding an edge that was not counted")
+	}
+
+	if _, err := b.Build(); err == nil {
+		t.Error("expected an error for a missing edge")
+	}
+}
+
+func TestCSRTooLarge(t *testing.T) {
+	if _, err := graph.NewCSR(nil, math.MaxUint32+1); err == nil {
+		t.Error("expected an error for more nodes than fit into uint32")
+	}
+	if _, err := graph.NewCSRBuilder(math.MaxUint32 + 1); err == nil {
+		t.Error("expected an error for more nodes than fit into uint32")
+	}
+}
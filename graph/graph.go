@@ -6,6 +6,12 @@ type Graph interface {
 	Reverted() Graph
 }
 
+// MemorySizer is implemented by graphs which know the number of bytes they
+// occupy in memory.
+type MemorySizer interface {
+	MemoryFootprint() int
+}
+
 type Node int
 
 type Edge struct {
@@ -0,0 +1,126 @@
+package graph
+
+import "sort"
+
+type NormalizeOptions struct {
+	// KeepParallel keeps all parallel edges instead of only the cheapest one.
+	// They are still counted in the report.
+	KeepParallel bool
+	// Symmetrize adds the reverse of every edge whose reverse is missing.
+	Symmetrize bool
+	// Undirected treats the edges as undirected: (u, v) and (v, u) are
+	// parallel edges and Symmetrize has no effect. The edges are returned with
+	// From < To.
+	Undirected bool
+}
+
+type NormalizeReport struct {
+	SelfLoops     int
+	ParallelEdges int
+	AddedReverse  int
+}
+
+// Normalize removes self-loops and parallel edges from edges. Of parallel
+// edges only the cheapest one is kept. The returned edges are sorted by
+// source and target node; edges is reordered in place.
+func Normalize(edges []Edge, opts NormalizeOptions) ([]Edge, NormalizeReport) {
+	var report NormalizeReport
+
+	out := edges[:0]
+	for _, e := range edges {
+		if e.From == e.To {
+			report.SelfLoops++
+			continue
+		}
+		if opts.Undirected && e.From > e.To {
+			e = e.Reverted()
+		}
+		out = append(out, e)
+	}
+
+	sortEdges(out)
+
+	out, report.ParallelEdges = removeParallel(out, opts.KeepParallel)
+
+	if opts.Symmetrize && !opts.Undirected {
+		out, report.AddedReverse = symmetrize(out)
+	}
+
+	return out, report
+}
+
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Cost < b.Cost
+	})
+}
+
+// removeParallel expects edges to be sorted and returns the edges without
+// parallel edges as well as the number of parallel edges found.
+func removeParallel(edges []Edge, keep bool) ([]Edge, int) {
+	parallel := 0
+
+	out := edges[:0]
+	for i, e := range edges {
+		if i > 0 && sameEndpoints(e, edges[i-1]) {
+			parallel++
+			if !keep {
+				continue
+			}
+		}
+		out = append(out, e)
+	}
+
+	return out, parallel
+}
+
+// symmetrize expects edges to be sorted and adds the cheapest reverse edge for
+// every pair of nodes only connected in one direction. It returns the
+// resulting edges and the number of edges added.
+func symmetrize(edges []Edge) ([]Edge, int) {
+	reversed := make([]Edge, len(edges))
+	for i, e := range edges {
+		reversed[i] = e.Reverted()
+	}
+	sortEdges(reversed)
+
+	var added []Edge
+	i := 0
+	for j, r := range reversed {
+		if j > 0 && sameEndpoints(r, reversed[j-1]) {
+			continue
+		}
+
+		for i < len(edges) && less(edges[i], r) {
+			i++
+		}
+
+		if i < len(edges) && sameEndpoints(edges[i], r) {
+			continue
+		}
+		added = append(added, r)
+	}
+
+	out := append(edges, added...)
+	sortEdges(out)
+
+	return out, len(added)
+}
+
+func sameEndpoints(a, b Edge) bool {
+	return a.From == b.From && a.To == b.To
+}
+
+func less(a, b Edge) bool {
+	if a.From != b.From {
+		return a.From < b.From
+	}
+	return a.To < b.To
+}
@@ -0,0 +1,80 @@
+package graph_test
+
+import (
+	"reflect"
+	"route-planning/graph"
+	"testing"
+)
+
+func normalizeTestEdges() []graph.Edge {
+	return []graph.Edge{
+		{From: 1, To: 2, Cost: 5},
+		{From: 0, To: 1, Cost: 3},
+		{From: 2, To: 2, Cost: 1},
+		{From: 1, To: 2, Cost: 2},
+		{From: 2, To: 1, Cost: 7},
+		{From: 0, To: 1, Cost: 3},
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       graph.NormalizeOptions
+		wantEdges  []graph.Edge
+		wantReport graph.NormalizeReport
+	}{
+		{
+			name: "default",
+			wantEdges: []graph.Edge{
+				{From: 0, To: 1, Cost: 3},
+				{From: 1, To: 2, Cost: 2},
+				{From: 2, To: 1, Cost: 7},
+			},
+			wantReport: graph.NormalizeReport{SelfLoops: 1, ParallelEdges: 2},
+		},
+		{
+			name: "keep parallel",
+			opts: graph.NormalizeOptions{KeepParallel: true},
+			wantEdges: []graph.Edge{
+				{From: 0, To: 1, Cost: 3},
+				{From: 0, To: 1, Cost: 3},
+				{From: 1, To: 2, Cost: 2},
+				{From: 1, To: 2, Cost: 5},
+				{From: 2, To: 1, Cost: 7},
+			},
+			wantReport: graph.NormalizeReport{SelfLoops: 1, ParallelEdges: 2},
+		},
+		{
+			name: "symmetrize",
+			opts: graph.NormalizeOptions{Symmetrize: true},
+			wantEdges: []graph.Edge{
+				{From: 0, To: 1, Cost: 3},
+				{From: 1, To: 0, Cost: 3},
+				{From: 1, To: 2, Cost: 2},
+				{From: 2, To: 1, Cost: 7},
+			},
+			wantReport: graph.NormalizeReport{SelfLoops: 1, ParallelEdges: 2, AddedReverse: 1},
+		},
+		{
+			name: "undirected",
+			opts: graph.NormalizeOptions{Symmetrize: true, Undirected: true},
+			wantEdges: []graph.Edge{
+				{From: 0, To: 1, Cost: 3},
+				{From: 1, To: 2, Cost: 2},
+			},
+			wantReport: graph.NormalizeReport{SelfLoops: 1, ParallelEdges: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edges, report := graph.Normalize(normalizeTestEdges(), tt.opts)
+			if !reflect.DeepEqual(edges, tt.wantEdges) {
+				t.Errorf("expected edges %v, got %v", tt.wantEdges, edges)
+			}
+			if report != tt.wantReport {
+				t.Errorf("expected report %+v, got %+v", tt.wantReport, report)
+			}
+		})
+	}
+}
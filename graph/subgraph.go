@@ -0,0 +1,87 @@
+package graph
+
+import "fmt"
+
+// Subgraph is a graph extracted from a larger graph with its nodes renumbered
+// to 0..n-1.
+type Subgraph struct {
+	Graph Graph
+
+	// Original maps every node of Graph to its node in the original graph.
+	Original []Node
+	// Index maps nodes of the original graph to their node in Graph.
+	Index map[Node]Node
+}
+
+// InducedSubgraph returns the subgraph induced by nodes. Nodes are numbered in
+// the order they are given, duplicates are ignored. Nodes which are not in g
+// are an error.
+func InducedSubgraph(g Graph, nodes []Node) (Subgraph, error) {
+	for _, v := range nodes {
+		if v < 0 || int(v) >= g.N() {
+			return Subgraph{}, fmt.Errorf("node %d out of range [0, %d)", v, g.N())
+		}
+	}
+
+	return inducedSubgraph(g, nodes), nil
+}
+
+// inducedSubgraph returns the subgraph induced by nodes, which have to be in
+// g.
+func inducedSubgraph(g Graph, nodes []Node) Subgraph {
+	sub := Subgraph{
+		Original: make([]Node, 0, len(nodes)),
+		Index:    make(map[Node]Node, len(nodes)),
+	}
+
+	for _, v := range nodes {
+		if _, ok := sub.Index[v]; ok {
+			continue
+		}
+		sub.Index[v] = Node(len(sub.Original))
+		sub.Original = append(sub.Original, v)
+	}
+
+	var edges []Edge
+	for i, v := range sub.Original {
+		for _, e := range g.OutgoingEdges(v) {
+			w, ok := sub.Index[e.To]
+			if !ok {
+				continue
+			}
+			edges = append(edges, Edge{
+				From: Node(i),
+				To:   w,
+				Cost: e.Cost,
+			})
+		}
+	}
+
+	sub.Graph = NewAdjacencyList(edges, len(sub.Original))
+
+	return sub
+}
+
+// BoundingBoxSubgraph returns the subgraph induced by all nodes whose
+// coordinate lies within box. coords is indexed by the nodes of g.
+func BoundingBoxSubgraph(g Graph, coords []Coordinate, box BoundingBox) Subgraph {
+	var nodes []Node
+	for i := 0; i < g.N(); i++ {
+		if box.Contains(coords[i]) {
+			nodes = append(nodes, Node(i))
+		}
+	}
+
+	return inducedSubgraph(g, nodes)
+}
+
+// Coordinates returns the coordinates of the subgraph's nodes given the
+// coordinates of the original graph.
+func (s Subgraph) Coordinates(coords []Coordinate) []Coordinate {
+	out := make([]Coordinate, len(s.Original))
+	for i, v := range s.Original {
+		out[i] = coords[v]
+	}
+
+	return out
+}
@@ -0,0 +1,79 @@
+package graph_test
+
+import (
+	"reflect"
+	"route-planning/graph"
+	"testing"
+)
+
+var subgraphTestEdges = []graph.Edge{
+	{From: 0, To: 1, Cost: 1},
+	{From: 1, To: 2, Cost: 2},
+	{From: 2, To: 0, Cost: 3},
+	{From: 2, To: 3, Cost: 4},
+	{From: 3, To: 1, Cost: 5},
+}
+
+func TestInducedSubgraph(t *testing.T) {
+	g := graph.NewAdjacencyList(subgraphTestEdges, 4)
+
+	sut, err := graph.InducedSubgraph(g, []graph.Node{3, 2, 1, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := sut.Graph.N(); n != 3 {
+		t.Fatalf("expected 3 nodes, got %d", n)
+	}
+
+	if expected := []graph.Node{3, 2, 1}; !reflect.DeepEqual(sut.Original, expected) {
+		t.Errorf("expected mapping %v, got %v", expected, sut.Original)
+	}
+
+	expected := [][]graph.Edge{
+		{{From: 0, To: 2, Cost: 5}},
+		{{From: 1, To: 0, Cost: 4}},
+		{{From: 2, To: 1, Cost: 2}},
+	}
+
+	for i, want := range expected {
+		if got := sut.Graph.OutgoingEdges(graph.Node(i)); !reflect.DeepEqual(got, want) {
+			t.Errorf("outgoing edges of %d: expected %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestInducedSubgraphInvalidNode(t *testing.T) {
+	g := graph.NewAdjacencyList(subgraphTestEdges, 4)
+
+	for _, v := range []graph.Node{4, -1} {
+		if _, err := graph.InducedSubgraph(g, []graph.Node{0, v}); err == nil {
+			t.Errorf("expected an error for node %d", v)
+		}
+	}
+}
+
+func TestBoundingBoxSubgraph(t *testing.T) {
+	g := graph.NewAdjacencyList(subgraphTestEdges, 4)
+	coords := []graph.Coordinate{
+		{Lat: 48.1, Lon: 11.5},
+		{Lat: 48.2, Lon: 11.6},
+		{Lat: 52.5, Lon: 13.4},
+		{Lat: 48.3, Lon: 11.7},
+	}
+
+	box := graph.BoundingBox{
+		Min: graph.Coordinate{Lat: 48, Lon: 11},
+		Max: graph.Coordinate{Lat: 49, Lon: 12},
+	}
+
+	sut := graph.BoundingBoxSubgraph(g, coords, box)
+
+	if expected := []graph.Node{0, 1, 3}; !reflect.DeepEqual(sut.Original, expected) {
+		t.Errorf("expected mapping %v, got %v", expected, sut.Original)
+	}
+
+	if expected := []graph.Coordinate{coords[0], coords[1], coords[3]}; !reflect.DeepEqual(sut.Coordinates(coords), expected) {
+		t.Errorf("expected coordinates %v, got %v", expected, sut.Coordinates(coords))
+	}
+}
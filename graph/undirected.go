@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// maxCompactSize is the largest number of nodes or edges of the graphs
+// storing them as uint32.
+const maxCompactSize = math.MaxUint32
+
+type undirectedEdge struct {
+	u    uint32
+	v    uint32
+	cost float64
+}
+
+// undirected stores every edge once. incident[offsets[v]:offsets[v+1]] are
+// the indices of the edges incident to v.
+type undirected struct {
+	edges    []undirectedEdge
+	offsets  []int
+	incident []uint32
+}
+
+// NewUndirected returns a graph in which every edge can be traversed in both
+// directions. Each edge must only be contained once in edges. The nodes and
+// edges are numbered with uint32, larger graphs are an error.
+func NewUndirected(edges []Edge, n int) (Graph, error) {
+	if n > maxCompactSize || len(edges) > maxCompactSize {
+		return nil, fmt.Errorf("undirected graph with %d nodes and %d edges exceeds the limit of %d", n, len(edges), uint64(maxCompactSize))
+	}
+
+	g := &undirected{
+		edges:   make([]undirectedEdge, len(edges)),
+		offsets: make([]int, n+1),
+	}
+
+	for i, e := range edges {
+		g.edges[i] = undirectedEdge{
+			u:    uint32(e.From),
+			v:    uint32(e.To),
+			cost: e.Cost,
+		}
+
+		g.offsets[e.From+1]++
+		if e.From != e.To {
+			g.offsets[e.To+1]++
+		}
+	}
+
+	for v := 0; v < n; v++ {
+		g.offsets[v+1] += g.offsets[v]
+	}
+
+	g.incident = make([]uint32, g.offsets[n])
+	next := make([]int, n)
+	copy(next, g.offsets[:n])
+
+	for i, e := range g.edges {
+		g.incident[next[e.u]] = uint32(i)
+		next[e.u]++
+		if e.u != e.v {
+			g.incident[next[e.v]] = uint32(i)
+			next[e.v]++
+		}
+	}
+
+	return g, nil
+}
+
+func (g *undirected) OutgoingEdges(v Node) []Edge {
+	incident := g.incident[g.offsets[v]:g.offsets[v+1]]
+	edges := make([]Edge, len(incident))
+	for i, idx := range incident {
+		e := g.edges[idx]
+		w := Node(e.v)
+		if w == v {
+			w = Node(e.u)
+		}
+		edges[i] = Edge{
+			From: v,
+			To:   w,
+			Cost: e.cost,
+		}
+	}
+
+	return edges
+}
+
+func (g *undirected) N() int {
+	return len(g.offsets) - 1
+}
+
+// Reverted returns the graph itself as an undirected graph equals its reverse.
+func (g *undirected) Reverted() Graph {
+	return g
+}
+
+func (g *undirected) MemoryFootprint() int {
+	return int(unsafe.Sizeof(*g)) +
+		cap(g.edges)*int(unsafe.Sizeof(undirectedEdge{})) +
+		cap(g.offsets)*int(unsafe.Sizeof(int(0))) +
+		cap(g.incident)*int(unsafe.Sizeof(uint32(0)))
+}
@@ -0,0 +1,48 @@
+package graph_test
+
+import (
+	"math"
+	"reflect"
+	"route-planning/graph"
+	"testing"
+)
+
+func TestNewUndirected(t *testing.T) {
+	edges := []graph.Edge{
+		{From: 0, To: 1, Cost: 1},
+		{From: 1, To: 2, Cost: 2},
+		{From: 2, To: 2, Cost: 3},
+	}
+
+	sut, err := graph.NewUndirected(edges, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sut.N() != 4 {
+		t.Fatalf("expected 4 nodes, got %d", sut.N())
+	}
+
+	expected := [][]graph.Edge{
+		{{From: 0, To: 1, Cost: 1}},
+		{{From: 1, To: 0, Cost: 1}, {From: 1, To: 2, Cost: 2}},
+		{{From: 2, To: 1, Cost: 2}, {From: 2, To: 2, Cost: 3}},
+		{},
+	}
+
+	for i, want := range expected {
+		if got := sut.OutgoingEdges(graph.Node(i)); !reflect.DeepEqual(got, want) {
+			t.Errorf("outgoing edges of %d: expected %v, got %v", i, want, got)
+		}
+	}
+
+	if sut.Reverted() != sut {
+		t.Errorf("expected Reverted to return the graph itself")
+	}
+}
+
+func TestNewUndirectedTooLarge(t *testing.T) {
+	if _, err := graph.NewUndirected(nil, math.MaxUint32+1); err == nil {
+		t.Error("expected an error for more nodes than fit into uint32")
+	}
+}
@@ -0,0 +1,405 @@
+package graphio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"route-planning/graph"
+	"unsafe"
+)
+
+// The binary cache stores a graph in CSR format so that it can be loaded by
+// mapping the file into memory. All values are little endian and every array
+// starts at a multiple of 8 bytes:
+//
+//	magic "RPGCACHE", version uint32, flags uint32, n uint64, m uint64,
+//	metadata length uint64, metadata JSON (padded),
+//	CRC-32C of the header and metadata as uint64,
+//	offsets [n+1]uint64, costs [m]float64, coordinates [n][2]float64 if
+//	flagged, targets [m]uint32 (padded),
+//	CRC-32C of the arrays as uint64.
+//
+// Only the checksum of the header is checked on every load, checking the
+// arrays takes as long as reading them.
+const (
+	cacheMagic        = "RPGCACHE"
+	cacheVersion      = 1
+	cacheHeaderSize   = 40
+	cacheChecksumSize = 8
+
+	cacheHasCoordinates = 1 << 0
+)
+
+// ErrCacheVersion is returned when loading a cache written by an incompatible
+// version.
+var ErrCacheVersion = errors.New("unsupported cache version")
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// CacheMetadata describes the input a cache was built from. It allows
+// detecting stale caches.
+type CacheMetadata struct {
+	Source        string `json:"source"`
+	SourceSize    int64  `json:"sourceSize"`
+	SourceModTime int64  `json:"sourceModTime"`
+	// Options describes all settings affecting the graph, e.g. the format or
+	// the routing profile.
+	Options string `json:"options"`
+}
+
+// NewCacheMetadata returns the metadata of a cache built from source with
+// options.
+func NewCacheMetadata(source, options string) (CacheMetadata, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return CacheMetadata{}, err
+	}
+
+	abs, err := filepath.Abs(source)
+	if err != nil {
+		return CacheMetadata{}, err
+	}
+
+	return CacheMetadata{
+		Source:        abs,
+		SourceSize:    info.Size(),
+		SourceModTime: info.ModTime().UnixNano(),
+		Options:       options,
+	}, nil
+}
+
+// Cache is a graph loaded from a binary cache. It provides the graph and
+// coordinates like an input.
+type Cache struct {
+	Graph       *graph.CSR
+	Coordinates []graph.Coordinate
+	Metadata    CacheMetadata
+
+	unmap func() error
+}
+
+func (c *Cache) LoadGraph() ([]graph.Edge, int, error) {
+	return c.Graph.Edges(), c.Graph.N(), nil
+}
+
+func (c *Cache) LoadCoordinates() ([]graph.Coordinate, error) {
+	if c.Coordinates == nil {
+		return nil, errors.New("cache contains no coordinates")
+	}
+	return c.Coordinates, nil
+}
+
+// Close releases the mapped file. The graph and coordinates must not be used
+// afterwards.
+func (c *Cache) Close() error {
+	return c.unmap()
+}
+
+// WriteCacheFile writes the cache to file. The file is replaced atomically so
+// that concurrent readers never see a partial cache.
+func WriteCacheFile(file string, g *graph.CSR, coords []graph.Coordinate, meta CacheMetadata) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = WriteCache(tmp, g, coords, meta)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+// WriteCache writes g, the optional coordinates and meta in the binary cache
+// format.
+func WriteCache(w io.Writer, g *graph.CSR, coords []graph.Coordinate, meta CacheMetadata) error {
+	if coords != nil {
+		if err := MatchCoordinates(coords, g.N()); err != nil {
+			return err
+		}
+	}
+
+	metadata, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	cw := &cacheWriter{w: bw, crc: crc32.New(castagnoli)}
+
+	var flags uint32
+	if coords != nil {
+		flags |= cacheHasCoordinates
+	}
+
+	cw.write([]byte(cacheMagic))
+	cw.uint32s([]uint32{cacheVersion, flags})
+	cw.uint64s([]uint64{uint64(g.N()), uint64(len(g.Targets)), uint64(len(metadata))})
+	cw.write(metadata)
+	cw.pad()
+	cw.checksum()
+
+	cw.uint64s(g.Offsets)
+	cw.float64s(g.Costs)
+	for _, c := range coords {
+		cw.float64s([]float64{c.Lat, c.Lon})
+	}
+	cw.uint32s(g.Targets)
+	cw.pad()
+	cw.checksum()
+
+	if cw.err != nil {
+		return fmt.Errorf("error writing cache: %w", cw.err)
+	}
+
+	return bw.Flush()
+}
+
+// cacheWriter encodes values in little endian and keeps the first error.
+type cacheWriter struct {
+	w io.Writer
+	// crc is the checksum of the data written since the last checksum.
+	crc hash.Hash32
+	n   int
+	err error
+	buf [8]byte
+}
+
+func (cw *cacheWriter) write(data []byte) {
+	if cw.err != nil {
+		return
+	}
+	n, err := cw.w.Write(data)
+	cw.crc.Write(data[:n])
+	cw.n += n
+	cw.err = err
+}
+
+// checksum writes the checksum of the data written since the last checksum.
+func (cw *cacheWriter) checksum() {
+	cw.uint64s([]uint64{uint64(cw.crc.Sum32())})
+	cw.crc.Reset()
+}
+
+func (cw *cacheWriter) pad() {
+	if rest := cw.n % 8; rest != 0 {
+		cw.write(make([]byte, 8-rest))
+	}
+}
+
+func (cw *cacheWriter) uint32s(values []uint32) {
+	for _, v := range values {
+		binary.LittleEndian.PutUint32(cw.buf[:4], v)
+		cw.write(cw.buf[:4])
+	}
+}
+
+func (cw *cacheWriter) uint64s(values []uint64) {
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(cw.buf[:], v)
+		cw.write(cw.buf[:])
+	}
+}
+
+func (cw *cacheWriter) float64s(values []float64) {
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(cw.buf[:], math.Float64bits(v))
+		cw.write(cw.buf[:])
+	}
+}
+
+// LoadCache maps the cache file into memory. The arrays of the graph point
+// directly into the mapping on little endian machines. Their checksum is only
+// checked if verify is set, but the graph is always checked to be well-formed.
+func LoadCache(file string, verify bool) (*Cache, error) {
+	data, unmap, err := mapFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error opening cache %s: %w", file, err)
+	}
+
+	c, err := parseCache(data, verify)
+	if err != nil {
+		unmap()
+		return nil, fmt.Errorf("error loading cache %s: %w", file, err)
+	}
+
+	c.unmap = unmap
+	return c, nil
+}
+
+func parseCache(data []byte, verify bool) (*Cache, error) {
+	if len(data) < cacheHeaderSize || !bytes.Equal(data[:8], []byte(cacheMagic)) {
+		return nil, errors.New("not a graph cache")
+	}
+
+	if version := binary.LittleEndian.Uint32(data[8:]); version != cacheVersion {
+		return nil, fmt.Errorf("%w %d, expected %d", ErrCacheVersion, version, cacheVersion)
+	}
+
+	flags := binary.LittleEndian.Uint32(data[12:])
+	n := binary.LittleEndian.Uint64(data[16:])
+	m := binary.LittleEndian.Uint64(data[24:])
+	metaLen := binary.LittleEndian.Uint64(data[32:])
+
+	// Bound the untrusted sizes by the file size before computing with them,
+	// every node takes at least 8 and every edge at least 12 bytes.
+	size := uint64(len(data))
+	if metaLen > size || n >= size/8 || m > size/12 {
+		return nil, fmt.Errorf("sizes of %d nodes, %d edges and %d bytes of metadata exceed the cache size %d", n, m, metaLen, size)
+	}
+
+	headerEnd := align8(cacheHeaderSize + metaLen)
+	arrays := (n+1)*8 + m*8 + align8(m*4)
+	if flags&cacheHasCoordinates != 0 {
+		arrays += n * 16
+	}
+	if expected := headerEnd + arrays + 2*cacheChecksumSize; expected != size {
+		return nil, fmt.Errorf("expected %d bytes for %d nodes and %d edges but got %d", expected, n, m, size)
+	}
+
+	header := data[:headerEnd]
+	if !validChecksum(header, data[headerEnd:]) {
+		return nil, errors.New("header checksum mismatch, the cache is corrupted")
+	}
+
+	c := &Cache{}
+	if err := json.Unmarshal(header[cacheHeaderSize:cacheHeaderSize+metaLen], &c.Metadata); err != nil {
+		return nil, fmt.Errorf("error parsing metadata: %w", err)
+	}
+
+	body := data[headerEnd+cacheChecksumSize : size-cacheChecksumSize]
+	if verify && !validChecksum(body, data[size-cacheChecksumSize:]) {
+		return nil, errors.New("checksum mismatch, the cache is corrupted")
+	}
+
+	offset := uint64(0)
+	next := func(length uint64) []byte {
+		b := body[offset : offset+length]
+		offset += length
+		return b
+	}
+
+	c.Graph = &graph.CSR{
+		Offsets: uint64Slice(next((n + 1) * 8)),
+		Costs:   float64Slice(next(m * 8)),
+	}
+	if flags&cacheHasCoordinates != 0 {
+		c.Coordinates = coordinateSlice(next(n * 16))
+	}
+	c.Graph.Targets = uint32Slice(next(m * 4))
+
+	if err := checkCSR(c.Graph, n, m); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// checkCSR checks that the offsets of g are non-decreasing from 0 to m and
+// that all targets are nodes of the graph, so that corrupted caches cannot
+// result in out of range accesses.
+func checkCSR(g *graph.CSR, n, m uint64) error {
+	if g.Offsets[0] != 0 || g.Offsets[n] != m {
+		return fmt.Errorf("offsets reference edges [%d, %d) but the cache contains %d", g.Offsets[0], g.Offsets[n], m)
+	}
+
+	for v := uint64(0); v < n; v++ {
+		if g.Offsets[v] > g.Offsets[v+1] {
+			return fmt.Errorf("offsets of node %d decrease", v)
+		}
+	}
+
+	for i, w := range g.Targets {
+		if uint64(w) >= n {
+			return fmt.Errorf("target %d of edge %d out of range [0, %d)", w, i, n)
+		}
+	}
+
+	return nil
+}
+
+// validChecksum returns whether section matches the checksum at the start of
+// sum.
+func validChecksum(section, sum []byte) bool {
+	return binary.LittleEndian.Uint64(sum) == uint64(crc32.Checksum(section, castagnoli))
+}
+
+func align8(n uint64) uint64 {
+	return (n + 7) &^ 7
+}
+
+// nativeLittleEndian is set if the arrays of a cache can be used without
+// decoding.
+var nativeLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// canAlias returns whether b can be reinterpreted as an array of values with
+// the given alignment.
+func canAlias(b []byte, alignment uintptr) bool {
+	return nativeLittleEndian && len(b) > 0 && uintptr(unsafe.Pointer(&b[0]))%alignment == 0
+}
+
+func uint64Slice(b []byte) []uint64 {
+	if canAlias(b, 8) {
+		return unsafe.Slice((*uint64)(unsafe.Pointer(&b[0])), len(b)/8)
+	}
+
+	values := make([]uint64, len(b)/8)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	return values
+}
+
+func uint32Slice(b []byte) []uint32 {
+	if canAlias(b, 4) {
+		return unsafe.Slice((*uint32)(unsafe.Pointer(&b[0])), len(b)/4)
+	}
+
+	values := make([]uint32, len(b)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	return values
+}
+
+func float64Slice(b []byte) []float64 {
+	if canAlias(b, 8) {
+		return unsafe.Slice((*float64)(unsafe.Pointer(&b[0])), len(b)/8)
+	}
+
+	values := make([]float64, len(b)/8)
+	for i := range values {
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:]))
+	}
+	return values
+}
+
+func coordinateSlice(b []byte) []graph.Coordinate {
+	if canAlias(b, 8) {
+		return unsafe.Slice((*graph.Coordinate)(unsafe.Pointer(&b[0])), len(b)/16)
+	}
+
+	values := float64Slice(b)
+	coords := make([]graph.Coordinate, len(values)/2)
+	for i := range coords {
+		coords[i] = graph.Coordinate{Lat: values[2*i], Lon: values[2*i+1]}
+	}
+	return coords
+}
@@ -0,0 +1,129 @@
+package graphio_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"testing"
+)
+
+func writeTestCache(t *testing.T, coords []graph.Coordinate) (string, *graph.CSR, graphio.CacheMetadata) {
+	g := newCSR(t, []graph.Edge{
+		{From: 0, To: 1, Cost: 1.5},
+		{From: 1, To: 2, Cost: 2},
+		{From: 2, To: 0, Cost: 3},
+	}, 3)
+	meta := graphio.CacheMetadata{Source: "graph.gr", SourceSize: 42, SourceModTime: 7, Options: "format=dimacs"}
+
+	file := filepath.Join(t.TempDir(), "graph.cache")
+	if err := graphio.WriteCacheFile(file, g, coords, meta); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return file, g, meta
+}
+
+func TestCache(t *testing.T) {
+	file, g, meta := writeTestCache(t, geoJSONTestCoords)
+
+	c, err := graphio.LoadCache(file, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	if !reflect.DeepEqual(c.Graph, g) {
+		t.Errorf("expected graph %v, got %v", g, c.Graph)
+	}
+	if !reflect.DeepEqual(c.Coordinates, geoJSONTestCoords) {
+		t.Errorf("expected coordinates %v, got %v", geoJSONTestCoords, c.Coordinates)
+	}
+	if c.Metadata != meta {
+		t.Errorf("expected metadata %v, got %v", meta, c.Metadata)
+	}
+}
+
+func TestCacheWithoutCoordinates(t *testing.T) {
+	file, _, _ := writeTestCache(t, nil)
+
+	c, err := graphio.LoadCache(file, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	if _, err := c.LoadCoordinates(); err == nil {
+		t.Error("expected an error loading missing coordinates")
+	}
+}
+
+func TestCacheCorrupted(t *testing.T) {
+	file, _, _ := writeTestCache(t, geoJSONTestCoords)
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The test graph has 4 offsets, 3 costs and 3 coordinates before the
+	// targets.
+	offsets := cacheArraysStart(data)
+	costs := offsets + 4*8
+	targets := costs + 3*8 + 3*16
+
+	tests := []struct {
+		name   string
+		offset int
+		value  byte
+		// verified is set if the corruption is only detected by verifying
+		// the checksum of the arrays.
+		verified bool
+	}{
+		{name: "metadata", offset: 45, value: 'x'},
+		{name: "huge n", offset: 23, value: 0x7f},
+		{name: "huge m", offset: 31, value: 0x7f},
+		{name: "cost", offset: costs + 7, value: 0xff, verified: true},
+		{name: "target", offset: targets + 4, value: 3},
+		{name: "offset", offset: offsets + 8, value: 5},
+	}
+
+	for _, tt := range tests {
+		corrupted := append([]byte(nil), data...)
+		corrupted[tt.offset] = tt.value
+		if err := os.WriteFile(file, corrupted, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if c, err := graphio.LoadCache(file, false); tt.verified && err != nil {
+			t.Errorf("%s: unexpected error without verification: %v", tt.name, err)
+		} else if !tt.verified && err == nil {
+			c.Close()
+			t.Errorf("%s: expected an error", tt.name)
+		} else if err == nil {
+			c.Close()
+		}
+
+		if _, err := graphio.LoadCache(file, true); err == nil {
+			t.Errorf("%s: expected an error when verifying", tt.name)
+		}
+	}
+
+	outdated := append([]byte(nil), data...)
+	outdated[8] = 0
+	if err := os.WriteFile(file, outdated, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := graphio.LoadCache(file, false); !errors.Is(err, graphio.ErrCacheVersion) {
+		t.Errorf("expected ErrCacheVersion, got %v", err)
+	}
+}
+
+// cacheArraysStart returns the position of the offsets in a cache.
+func cacheArraysStart(data []byte) int {
+	metaLen := int(binary.LittleEndian.Uint64(data[32:]))
+	return (40+metaLen+7)&^7 + 8
+}
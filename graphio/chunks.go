@@ -0,0 +1,178 @@
+package graphio
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+)
+
+// defaultChunkSize is the approximate number of bytes parsed at once by a
+// worker.
+const defaultChunkSize = 4 * 1024 * 1024
+
+// chunk is a part of the input ending at a line boundary.
+type chunk struct {
+	seq  int
+	data []byte
+	// line is the number of the first line in the chunk.
+	line int
+}
+
+type chunkResult struct {
+	seq     int
+	value   interface{}
+	skipped []*ParseError
+	err     error
+}
+
+// chunkParser parses the lines of a chunk starting at line number line and
+// passes the errors of malformed lines to errs.
+type chunkParser func(data []byte, line int, errs *lineErrors) (interface{}, error)
+
+// parseChunks splits r into chunks of whole lines, parses them with parse on
+// the workers of opts and passes the results to merge in the order of the
+// input. At most two chunks per worker are held in memory at once. The first
+// line of r is numbered line. Skipped lines are added to errs in input order
+// and the first error in input order is returned. All goroutines have stopped
+// when parseChunks returns.
+func parseChunks(r *bufio.Reader, line int, opts parseOptions, errs *lineErrors, parse chunkParser, merge func(interface{}) error) error {
+	done := make(chan struct{})
+	readDone := make(chan struct{})
+	// resultsClosed is closed after all workers have stopped.
+	resultsClosed := make(chan struct{})
+	defer func() {
+		close(done)
+		<-readDone
+		<-resultsClosed
+	}()
+
+	jobs := make(chan chunk)
+	results := make(chan chunkResult)
+	// inFlight limits the chunks read but not yet merged.
+	inFlight := make(chan struct{}, 2*opts.workers)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(readDone)
+		defer close(jobs)
+		readErr <- readChunks(r, line, opts.chunkSize, func(c chunk) bool {
+			select {
+			case inFlight <- struct{}{}:
+			case <-done:
+				return false
+			}
+
+			select {
+			case jobs <- c:
+				return true
+			case <-done:
+				return false
+			}
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				chunkErrs := &lineErrors{src: errs.src, lenient: errs.lenient}
+				value, err := parse(c.data, c.line, chunkErrs)
+				select {
+				case results <- chunkResult{seq: c.seq, value: value, skipped: chunkErrs.skipped, err: err}:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(resultsClosed)
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]chunkResult)
+	next := 0
+	for res := range results {
+		pending[res.seq] = res
+
+		for {
+			res, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			<-inFlight
+
+			errs.skipped = append(errs.skipped, res.skipped...)
+			if res.err != nil {
+				return res.err
+			}
+			if err := merge(res.value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return <-readErr
+}
+
+// readChunks reads r in chunks of about size bytes ending at line boundaries
+// and passes them to send until send returns false.
+func readChunks(r *bufio.Reader, line, size int, send func(chunk) bool) error {
+	for seq := 0; ; seq++ {
+		data := make([]byte, size)
+		n, err := io.ReadFull(r, data)
+		data = data[:n]
+
+		last := false
+		switch err {
+		case nil:
+			// Complete the last line.
+			rest, err := r.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				return err
+			}
+			data = append(data, rest...)
+		case io.EOF, io.ErrUnexpectedEOF:
+			last = true
+		default:
+			return err
+		}
+
+		if len(data) > 0 && !send(chunk{seq: seq, data: data, line: line}) {
+			return nil
+		}
+		if last {
+			return nil
+		}
+
+		line += bytes.Count(data, []byte{'\n'})
+	}
+}
+
+// forEachLine calls handle for every line of data with its number, starting
+// at line. The line does not contain the line break.
+func forEachLine(data []byte, line int, handle func(text []byte, line int) error) error {
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, '\n')
+		text := data
+		if end >= 0 {
+			text, data = data[:end], data[end+1:]
+		} else {
+			data = nil
+		}
+
+		if err := handle(text, line); err != nil {
+			return err
+		}
+		line++
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package graphio_test
+
+import (
+	"fmt"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParallelDIMACSInput(t *testing.T) {
+	var data strings.Builder
+	var expected []graph.Edge
+	data.WriteString("c many small chunks\np sp 100 1000\n")
+	for i := 0; i < 1000; i++ {
+		e := graph.Edge{From: graph.Node(i % 100), To: graph.Node(i * 7 % 100), Cost: float64(i)}
+		expected = append(expected, e)
+		fmt.Fprintf(&data, "a %d %d %d\n", e.From+1, e.To+1, int(e.Cost))
+		if i%10 == 0 {
+			data.WriteString("c comment\n\n")
+		}
+	}
+	file := writeTestFile(t, "graph.gr", data.String())
+
+	for _, workers := range []int{1, 3, 8} {
+		edges, n, err := graphio.NewDIMANCSInput(file, graphio.WithWorkers(workers), graphio.WithChunkSize(64)).LoadGraph()
+		if err != nil {
+			t.Fatalf("%d workers: unexpected error: %v", workers, err)
+		}
+
+		if n != 100 || !reflect.DeepEqual(edges, expected) {
+			t.Errorf("%d workers: edges differ from the input order", workers)
+		}
+	}
+}
+
+func TestParallelDIMACSInputError(t *testing.T) {
+	data := "p sp 3 4\na 1 2 1\na 2 3 x\na 3 1 1\nb\n"
+
+	goroutines := runtime.NumGoroutine()
+
+	// The first error in input order is reported.
+	for i := 0; i < 10; i++ {
+		_, _, err := graphio.NewDIMACSInputFromReader(strings.NewReader(data), graphio.WithWorkers(4), graphio.WithChunkSize(16)).LoadGraph()
+		if err == nil || !strings.Contains(err.Error(), "error parsing {w}") {
+			t.Fatalf("expected the error of the second arc, got %v", err)
+		}
+
+		// All goroutines have stopped once the error is returned.
+		if n := runtime.NumGoroutine(); n != goroutines {
+			t.Fatalf("expected %d goroutines, got %d", goroutines, n)
+		}
+	}
+}
+
+func TestParallelSNAPInput(t *testing.T) {
+	var data strings.Builder
+	var expectedIDs []string
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&data, "%d %d\n", 1000-i, 2000+i)
+		expectedIDs = append(expectedIDs, fmt.Sprint(1000-i), fmt.Sprint(2000+i))
+	}
+
+	in := graphio.NewSNAPInputFromReader(strings.NewReader(data.String()), graphio.SNAPOptions{Compact: true}, graphio.WithWorkers(4), graphio.WithChunkSize(32))
+
+	ids, err := in.LoadNodeIDs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Compact IDs are assigned in input order regardless of the workers.
+	if !reflect.DeepEqual(ids, expectedIDs) {
+		t.Errorf("expected node IDs in input order, got %v", ids)
+	}
+}
@@ -0,0 +1,68 @@
+package graphio
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+)
+
+// decompress returns a reader of the decompressed data if r is compressed
+// with gzip or bzip2 and a reader of the unchanged data otherwise. The
+// compression is detected by the magic bytes at the start of the data.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(len(bzip2Magic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("error reading gzip data: %w", err)
+		}
+		return zr, nil
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return bzip2.NewReader(br), nil
+	default:
+		return br, nil
+	}
+}
+
+// compressedFile is a file read through a decompressor.
+type compressedFile struct {
+	io.Reader
+	f *os.File
+}
+
+func (cf compressedFile) Close() error {
+	return cf.f.Close()
+}
+
+// openFile opens file for reading and transparently decompresses gzip and
+// bzip2 files.
+func openFile(file string) (io.ReadCloser, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := decompress(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return compressedFile{Reader: r, f: f}, nil
+}
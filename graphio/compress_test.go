@@ -0,0 +1,59 @@
+package graphio_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"testing"
+)
+
+const compressTestGraph = "p sp 2 1\na 1 2 3\n"
+
+// compressTestGraphBzip2 is compressTestGraph compressed with bzip2, which
+// cannot be written with the standard library.
+var compressTestGraphBzip2 = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x83, 0x29, 0x50, 0x48, 0x00, 0x00,
+	0x07, 0xd9, 0x80, 0x00, 0x10, 0x40, 0x00, 0x38, 0x00, 0x20, 0x00, 0x48, 0x00, 0x20, 0x00, 0x31,
+	0x0c, 0x08, 0x20, 0xd3, 0x35, 0x09, 0xb8, 0x3d, 0x45, 0xd1, 0xd8, 0xbc, 0x5d, 0xc9, 0x14, 0xe1,
+	0x42, 0x42, 0x0c, 0xa5, 0x41, 0x20,
+}
+
+func TestCompressedInput(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(compressTestGraph))
+	zw.Close()
+
+	// The compression is detected regardless of the file extension.
+	files := map[string]string{
+		"gzip":  writeTestFile(t, "graph.gr", gz.String()),
+		"bzip2": writeTestFile(t, "graph.gr.bz2", string(compressTestGraphBzip2)),
+		"plain": writeTestFile(t, "graph.gr.gz", compressTestGraph),
+	}
+
+	expected := []graph.Edge{{From: 0, To: 1, Cost: 3}}
+
+	for name, file := range files {
+		edges, n, err := graphio.NewDIMANCSInput(file).LoadGraph()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if n != 2 || !reflect.DeepEqual(edges, expected) {
+			t.Errorf("%s: expected 2 nodes and %v, got %d nodes and %v", name, expected, n, edges)
+		}
+	}
+}
+
+func TestDIMACSInputFromReader(t *testing.T) {
+	edges, n, err := graphio.NewDIMACSInputFromReader(bytes.NewReader(compressTestGraphBzip2)).LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := []graph.Edge{{From: 0, To: 1, Cost: 3}}; n != 2 || !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected 2 nodes and %v, got %d nodes and %v", expected, n, edges)
+	}
+}
@@ -0,0 +1,125 @@
+package graphio
+
+import (
+	"bufio"
+	"fmt"
+	"route-planning/graph"
+	"strconv"
+	"strings"
+)
+
+// dimacsCoordinateScale is the factor by which coordinates in DIMACS .co
+// files are multiplied to store them as integers.
+const dimacsCoordinateScale = 1e6
+
+type dimacsCoordinateInput struct {
+	file string
+}
+
+// NewDIMACSCoordinateInput reads node coordinates from a DIMACS challenge 9
+// .co file. The x value of a node is its longitude, the y value its latitude.
+func NewDIMACSCoordinateInput(file string) CoordinateInput {
+	return &dimacsCoordinateInput{file: file}
+}
+
+func (dci dimacsCoordinateInput) LoadCoordinates() ([]graph.Coordinate, error) {
+	f, err := openFile(dci.file)
+	if err != nil {
+		return nil, fmt.Errorf("error opening coordinate file %s: %w", dci.file, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	n, err := parseCoordinateProblemLine(scanner)
+	if err != nil {
+		return nil, fmt.Errorf("DIMACS coordinate parsing failed: \n\t%w", err)
+	}
+
+	coords, err := parseCoordinateLines(scanner, n)
+	if err != nil {
+		return nil, fmt.Errorf("DIMACS coordinate parsing failed: \n\t%w", err)
+	}
+
+	return coords, nil
+}
+
+// MatchCoordinates checks that coords contains a coordinate for every node of
+// a graph with n nodes.
+func MatchCoordinates(coords []graph.Coordinate, n int) error {
+	if len(coords) != n {
+		return fmt.Errorf("got %d coordinates for a graph with %d nodes", len(coords), n)
+	}
+	return nil
+}
+
+func parseCoordinateProblemLine(scanner *bufio.Scanner) (int, error) {
+	problemLine := nextRelevantLine(scanner)
+	fields := strings.Fields(problemLine)
+	if len(fields) != 5 || fields[0] != "p" || fields[1] != "aux" || fields[2] != "sp" || fields[3] != "co" {
+		return 0, fmt.Errorf("expected problem line of format 'p aux sp co {n}', but got %q", problemLine)
+	}
+
+	n, err := strconv.Atoi(fields[4])
+	if err != nil {
+		return 0, fmt.Errorf("error parsing {n} in problem line: %w", err)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("negative {n} %d in problem line", n)
+	}
+
+	return n, nil
+}
+
+func parseCoordinateLines(scanner *bufio.Scanner, n int) ([]graph.Coordinate, error) {
+	// The coordinates grow with the largest node seen, n is only trusted once
+	// every node has a coordinate.
+	coords := make([]graph.Coordinate, 0, preallocated(n))
+	seen := make([]bool, 0, preallocated(n))
+	count := 0
+
+	for line := nextRelevantLine(scanner); line != ""; line = nextRelevantLine(scanner) {
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[0] != "v" {
+			return nil, fmt.Errorf("expected coordinate line to have format 'v {id} {x} {y}' but got %q", line)
+		}
+
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing {id} of coordinate line %q: %w", line, err)
+		}
+		if id < 1 || id > n {
+			return nil, fmt.Errorf("node %d of coordinate line %q out of range [1, %d]", id, line, n)
+		}
+		if id > len(coords) {
+			coords = append(coords, make([]graph.Coordinate, id-len(coords))...)
+			seen = append(seen, make([]bool, id-len(seen))...)
+		}
+		if seen[id-1] {
+			return nil, fmt.Errorf("duplicate coordinate for node %d", id)
+		}
+
+		x, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing {x} of coordinate line %q: %w", line, err)
+		}
+
+		y, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing {y} of coordinate line %q: %w", line, err)
+		}
+
+		coords[id-1] = graph.Coordinate{
+			Lat: float64(y) / dimacsCoordinateScale,
+			Lon: float64(x) / dimacsCoordinateScale,
+		}
+		seen[id-1] = true
+		count++
+	}
+
+	if count != n {
+		return nil, fmt.Errorf("expected %d coordinates but got %d", n, count)
+	}
+
+	return coords, nil
+}
@@ -0,0 +1,54 @@
+package graphio_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"testing"
+)
+
+func TestDIMACSCoordinateInput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "graph.co")
+	data := "c coordinates\np aux sp co 2\nv 2 -73530767 41085396\nv 1 13404954 52520008\n"
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	coords, err := graphio.NewDIMACSCoordinateInput(file).LoadCoordinates()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []graph.Coordinate{
+		{Lat: 52.520008, Lon: 13.404954},
+		{Lat: 41.085396, Lon: -73.530767},
+	}
+
+	if !reflect.DeepEqual(coords, expected) {
+		t.Errorf("expected %v, got %v", expected, coords)
+	}
+
+	if err := graphio.MatchCoordinates(coords, 3); err == nil {
+		t.Errorf("expected error matching 2 coordinates to 3 nodes")
+	}
+}
+
+func TestDIMACSCoordinateInputInvalid(t *testing.T) {
+	tests := map[string]string{
+		"negative n":    "p aux sp co -1\n",
+		"missing nodes": "p aux sp co 4000000000\nv 1 0 0\n",
+	}
+
+	for name, data := range tests {
+		file := filepath.Join(t.TempDir(), "graph.co")
+		if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := graphio.NewDIMACSCoordinateInput(file).LoadCoordinates(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
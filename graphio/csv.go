@@ -0,0 +1,304 @@
+package graphio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"route-planning/graph"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions describes the layout of a delimited edge list. Columns are
+// given by their name in the header row or by their 0-based index.
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' if zero.
+	Comma rune
+	// Header marks the first row as column names.
+	Header bool
+
+	From string
+	To   string
+	// Cost is the column of the edge cost. All edges cost 1 if empty.
+	Cost string
+	// Metrics are additional columns loaded as alternative edge costs.
+	Metrics []string
+
+	// OneBased marks numeric node IDs starting at 1 instead of 0.
+	OneBased bool
+	// StringIDs allows arbitrary node IDs, which are numbered in the order of
+	// their first appearance.
+	StringIDs bool
+}
+
+// DefaultCSVOptions reads the first three columns as from, to and cost.
+var DefaultCSVOptions = CSVOptions{From: "0", To: "1", Cost: "2"}
+
+// CSVInput reads edge lists from delimited text files. The data is only
+// parsed once.
+type CSVInput interface {
+	GraphInput
+	// LoadMetrics returns the values of every metric column indexed by edge.
+	LoadMetrics() (map[string][]float64, error)
+	// LoadNodeIDs returns the original ID of every node.
+	LoadNodeIDs() ([]string, error)
+}
+
+type csvInput struct {
+	src   *source
+	opts  CSVOptions
+	parse parseOptions
+
+	graph   *csvGraph
+	skipped []*ParseError
+}
+
+type csvGraph struct {
+	edges   []graph.Edge
+	n       int
+	metrics map[string][]float64
+	ids     []string
+}
+
+// NewCSVInput reads a delimited edge list. Malformed rows can be skipped, see
+// WithLenient.
+func NewCSVInput(file string, opts CSVOptions, parseOpts ...ParseOption) CSVInput {
+	return &csvInput{src: fileSource(file), opts: opts, parse: newParseOptions(parseOpts)}
+}
+
+func NewCSVInputFromReader(r io.Reader, opts CSVOptions, parseOpts ...ParseOption) CSVInput {
+	return &csvInput{src: readerSource(r), opts: opts, parse: newParseOptions(parseOpts)}
+}
+
+func (ci *csvInput) LoadGraph() ([]graph.Edge, int, error) {
+	g, err := ci.load()
+	if err != nil {
+		return nil, 0, err
+	}
+	return g.edges, g.n, nil
+}
+
+func (ci *csvInput) LoadMetrics() (map[string][]float64, error) {
+	g, err := ci.load()
+	if err != nil {
+		return nil, err
+	}
+	return g.metrics, nil
+}
+
+func (ci *csvInput) LoadNodeIDs() ([]string, error) {
+	g, err := ci.load()
+	if err != nil {
+		return nil, err
+	}
+	return g.ids, nil
+}
+
+func (ci *csvInput) SkippedLines() []*ParseError {
+	return ci.skipped
+}
+
+func (ci *csvInput) load() (*csvGraph, error) {
+	if ci.graph != nil {
+		return ci.graph, nil
+	}
+
+	f, err := ci.src.open()
+	if err != nil {
+		return nil, fmt.Errorf("error opening graph file %s: %w", ci.src, err)
+	}
+	defer f.Close()
+
+	errs := &lineErrors{src: ci.src, lenient: ci.parse.lenient}
+	g, err := parseCSV(f, ci.opts, errs)
+	ci.skipped = errs.skipped
+	if err != nil {
+		return nil, fmt.Errorf("CSV parsing failed: \n\t%w", err)
+	}
+
+	ci.graph = g
+	return g, nil
+}
+
+// csvColumns are the resolved indices of the columns.
+type csvColumns struct {
+	from, to, cost int
+	metrics        []int
+}
+
+func parseCSV(r io.Reader, opts CSVOptions, errs *lineErrors) (*csvGraph, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = opts.Comma
+	if cr.Comma == 0 {
+		cr.Comma = ','
+	}
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	cr.TrimLeadingSpace = true
+	if cr.Comma == '\t' {
+		cr.LazyQuotes = true
+	}
+
+	var header []string
+	if opts.Header {
+		record, err := cr.Read()
+		if err != nil {
+			return nil, fmt.Errorf("error reading header: %w", inFile(csvParseError(err), errs.src))
+		}
+		header = append(header, record...)
+	}
+
+	cols, err := resolveCSVColumns(opts, header)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &csvGraph{metrics: make(map[string][]float64, len(opts.Metrics))}
+	nodes := newNodeMapper(opts.OneBased, opts.StringIDs)
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil {
+			err = parseCSVRecord(g, cr, record, cols, opts, nodes)
+		}
+		if err := errs.skip(csvParseError(err)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := nodes.checkSparse(); err != nil {
+		return nil, err
+	}
+
+	g.n = nodes.n
+	g.ids = nodes.ids()
+
+	return g, nil
+}
+
+// parseCSVRecord adds the edge and metric values of the record last read by
+// cr to g.
+func parseCSVRecord(g *csvGraph, cr *csv.Reader, record []string, cols csvColumns, opts CSVOptions, nodes *nodeMapper) (err error) {
+	// Forget the nodes of skipped records.
+	n := nodes.n
+	defer func() {
+		if err != nil {
+			nodes.rollback(n)
+		}
+	}()
+
+	from, err := nodes.node(csvField(record, cols.from))
+	if err != nil {
+		return csvErrorf(cr, record, cols.from, "invalid from node: %w", err)
+	}
+	to, err := nodes.node(csvField(record, cols.to))
+	if err != nil {
+		return csvErrorf(cr, record, cols.to, "invalid to node: %w", err)
+	}
+
+	cost := 1.0
+	if cols.cost >= 0 {
+		if cost, err = parseCSVFloat(record, cols.cost); err != nil {
+			return csvErrorf(cr, record, cols.cost, "invalid cost: %w", err)
+		}
+	}
+
+	// Only add the values once the whole record is known to be valid.
+	values := make([]float64, len(cols.metrics))
+	for i, col := range cols.metrics {
+		if values[i], err = parseCSVFloat(record, col); err != nil {
+			return csvErrorf(cr, record, col, "invalid %s: %w", opts.Metrics[i], err)
+		}
+	}
+
+	for i, value := range values {
+		g.metrics[opts.Metrics[i]] = append(g.metrics[opts.Metrics[i]], value)
+	}
+	g.edges = append(g.edges, graph.Edge{From: from, To: to, Cost: cost})
+
+	return nil
+}
+
+// csvErrorf returns a ParseError at column col of the record last read by cr.
+func csvErrorf(cr *csv.Reader, record []string, col int, format string, args ...interface{}) *ParseError {
+	line, column := cr.FieldPos(0)
+	if col < len(record) {
+		line, column = cr.FieldPos(col)
+	} else {
+		column = 0
+	}
+	return parseErrorf(line, column, format, args...)
+}
+
+// csvParseError converts errors of malformed records returned by csv.Reader
+// to ParseErrors.
+func csvParseError(err error) error {
+	var csvErr *csv.ParseError
+	if !errors.As(err, &csvErr) {
+		return err
+	}
+	return &ParseError{Line: csvErr.Line, Column: csvErr.Column, Err: csvErr.Err}
+}
+
+func resolveCSVColumns(opts CSVOptions, header []string) (csvColumns, error) {
+	resolve := func(name string) (int, error) {
+		for i, h := range header {
+			if strings.TrimSpace(h) == name {
+				return i, nil
+			}
+		}
+
+		i, err := strconv.Atoi(name)
+		if err != nil || i < 0 {
+			return 0, fmt.Errorf("unknown column %q", name)
+		}
+		return i, nil
+	}
+
+	var cols csvColumns
+	var err error
+
+	if cols.from, err = resolve(opts.From); err != nil {
+		return cols, err
+	}
+	if cols.to, err = resolve(opts.To); err != nil {
+		return cols, err
+	}
+
+	cols.cost = -1
+	if opts.Cost != "" {
+		if cols.cost, err = resolve(opts.Cost); err != nil {
+			return cols, err
+		}
+	}
+
+	for _, metric := range opts.Metrics {
+		col, err := resolve(metric)
+		if err != nil {
+			return cols, err
+		}
+		cols.metrics = append(cols.metrics, col)
+	}
+
+	return cols, nil
+}
+
+func csvField(record []string, col int) string {
+	if col >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[col])
+}
+
+func parseCSVFloat(record []string, col int) (float64, error) {
+	field := csvField(record, col)
+	if field == "" {
+		return 0, fmt.Errorf("missing column %d", col)
+	}
+	return strconv.ParseFloat(field, 64)
+}
@@ -0,0 +1,87 @@
+package graphio_test
+
+import (
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"strings"
+	"testing"
+)
+
+func TestCSVInput(t *testing.T) {
+	data := "# exported network\nsrc,dst,length,time\nA,B,3,1.5\nB, C ,4,2\nC,A,5,\"2.5\"\n"
+
+	in := graphio.NewCSVInputFromReader(strings.NewReader(data), graphio.CSVOptions{
+		Header:    true,
+		From:      "src",
+		To:        "dst",
+		Cost:      "3",
+		Metrics:   []string{"length"},
+		StringIDs: true,
+	})
+
+	edges, n, err := in.LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []graph.Edge{{From: 0, To: 1, Cost: 1.5}, {From: 1, To: 2, Cost: 2}, {From: 2, To: 0, Cost: 2.5}}
+	if n != 3 || !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected 3 nodes and %v, got %d nodes and %v", expected, n, edges)
+	}
+
+	// The parsed data is kept, so the consumed reader is not read again.
+	metrics, err := in.LoadMetrics()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := map[string][]float64{"length": {3, 4, 5}}; !reflect.DeepEqual(metrics, expected) {
+		t.Errorf("expected metrics %v, got %v", expected, metrics)
+	}
+
+	ids, err := in.LoadNodeIDs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"A", "B", "C"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected node IDs %v, got %v", expected, ids)
+	}
+}
+
+func TestCSVInputNumericIDs(t *testing.T) {
+	data := "1\t4\n4\t2\n"
+
+	edges, n, err := graphio.NewCSVInputFromReader(strings.NewReader(data), graphio.CSVOptions{
+		Comma:    '\t',
+		From:     "0",
+		To:       "1",
+		OneBased: true,
+	}).LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []graph.Edge{{From: 0, To: 3, Cost: 1}, {From: 3, To: 1, Cost: 1}}
+	if n != 4 || !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected 4 nodes and %v, got %d nodes and %v", expected, n, edges)
+	}
+}
+
+func TestCSVInputInvalid(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		opts graphio.CSVOptions
+	}{
+		"unknown column": {"a,b\n1,2\n", graphio.CSVOptions{Header: true, From: "a", To: "c"}},
+		"invalid cost":   {"0,1,x\n", graphio.DefaultCSVOptions},
+		"missing cost":   {"0,1\n", graphio.DefaultCSVOptions},
+		"negative id":    {"0,-1,1\n", graphio.DefaultCSVOptions},
+		"zero one-based": {"0,1\n", graphio.CSVOptions{From: "0", To: "1", OneBased: true}},
+	}
+
+	for name, test := range tests {
+		if _, _, err := graphio.NewCSVInputFromReader(strings.NewReader(test.data), test.opts).LoadGraph(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
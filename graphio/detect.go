@@ -0,0 +1,224 @@
+package graphio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format names an input format as accepted by the --format flag.
+type Format string
+
+const (
+	FormatDIMACS Format = "dimacs"
+	FormatMTX    Format = "mtx"
+	FormatMETIS  Format = "metis"
+	FormatCSV    Format = "csv"
+	FormatTSV    Format = "tsv"
+	FormatSNAP   Format = "snap"
+	FormatOSMPBF Format = "osm-pbf"
+	FormatOSMXML Format = "osm-xml"
+)
+
+// sniffSize is the number of decompressed bytes inspected to detect the
+// format.
+const sniffSize = 64 * 1024
+
+// DetectFormat returns the format of file judging by its first bytes.
+// Compressed files are detected by their content.
+func DetectFormat(file string) (Format, error) {
+	f, err := openFile(file)
+	if err != nil {
+		return "", fmt.Errorf("error opening graph file %s: %w", file, err)
+	}
+	defer f.Close()
+
+	format, _, err := DetectFormatFromReader(f)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", file, err)
+	}
+
+	return format, nil
+}
+
+// DetectFormatFromReader returns the format of the data read from r and a
+// reader returning the same data, including the bytes inspected, but
+// already decompressed.
+func DetectFormatFromReader(r io.Reader) (Format, io.Reader, error) {
+	dr, err := decompress(r)
+	if err != nil {
+		return "", nil, err
+	}
+
+	br := bufio.NewReaderSize(dr, sniffSize)
+	data, err := br.Peek(sniffSize)
+	if err != nil && err != io.EOF && !errors.Is(err, bufio.ErrBufferFull) {
+		return "", nil, err
+	}
+
+	format, err := sniffFormat(data, err == io.EOF)
+	return format, br, err
+}
+
+// sniffFormat detects the format of data, the start of a file which is
+// complete if eof is set.
+func sniffFormat(data []byte, eof bool) (Format, error) {
+	if isOSMPBF(data) {
+		return FormatOSMPBF, nil
+	}
+
+	text := string(data)
+	if eof {
+		text = strings.TrimSuffix(text, "\n")
+	} else if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+		// Ignore the last line, which may be cut off.
+		text = text[:i]
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	first := strings.TrimSpace(strings.TrimPrefix(lines[0], "\ufeff"))
+	switch {
+	case first == "":
+		if strings.TrimSpace(text) == "" {
+			return "", errors.New("unable to detect the format of an empty input")
+		}
+	case strings.HasPrefix(strings.ToLower(first), "%%matrixmarket"):
+		return FormatMTX, nil
+	case strings.HasPrefix(first, "<"):
+		if strings.Contains(text, "<osm") {
+			return FormatOSMXML, nil
+		}
+		return "", errors.New("unable to detect the format: XML input is not an OpenStreetMap file")
+	}
+
+	if format, ok, err := sniffDIMACS(lines); ok {
+		return format, err
+	}
+
+	return sniffEdgeList(lines, eof)
+}
+
+// isOSMPBF returns whether data starts with the header blob of an
+// OpenStreetMap PBF file: the big-endian size of the blob header followed by
+// the header's type field "OSMHeader".
+func isOSMPBF(data []byte) bool {
+	const blobType = "OSMHeader"
+	if len(data) < 6+len(blobType) {
+		return false
+	}
+
+	size := binary.BigEndian.Uint32(data)
+	return size > 0 && size <= maxPBFBlobSize &&
+		data[4] == 0x0a && int(data[5]) == len(blobType) &&
+		string(data[6:6+len(blobType)]) == blobType
+}
+
+// sniffDIMACS detects DIMACS files by their problem line, which follows
+// comment lines starting with 'c'. ok reports whether the lines look like
+// DIMACS at all.
+func sniffDIMACS(lines []string) (format Format, ok bool, err error) {
+	for _, line := range lines {
+		if checkEmpty(line) {
+			continue
+		}
+
+		switch {
+		case line[0] == 'c' && (len(line) == 1 || line[1] == ' ' || line[1] == '\t'):
+			continue
+		case strings.HasPrefix(line, "p sp "):
+			return FormatDIMACS, true, nil
+		case strings.HasPrefix(line, "p aux sp co "):
+			return "", true, errors.New("input is a DIMACS coordinate file, pass it with --coordinates next to the graph")
+		case strings.HasPrefix(line, "p aux sp "):
+			return "", true, errors.New("input is a DIMACS query file, not a graph")
+		}
+
+		return "", false, nil
+	}
+
+	return "", false, nil
+}
+
+// sniffEdgeList distinguishes the line based formats: CSV and TSV by their
+// delimiters, METIS by its header line and adjacency lists and SNAP edge lists
+// by lines of two or three numbers.
+func sniffEdgeList(lines []string, eof bool) (Format, error) {
+	var data []string
+	hashComments := false
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "#"):
+			hashComments = true
+		case strings.HasPrefix(line, "%"):
+		case len(data) == 0 && strings.TrimSpace(line) == "":
+		default:
+			data = append(data, line)
+		}
+	}
+
+	if len(data) == 0 {
+		return "", errors.New("unable to detect the format: input only contains comments")
+	}
+
+	header := data[0]
+	switch {
+	case strings.Contains(header, ","):
+		return FormatCSV, nil
+	case strings.Contains(header, "\t") && !numeric(strings.Fields(header)):
+		return FormatTSV, nil
+	case !numeric(strings.Fields(header)):
+		return "", fmt.Errorf("unable to detect the format of a file starting with %q, use --format", header)
+	case hashComments:
+		// METIS only uses '%' for comments.
+		return FormatSNAP, nil
+	}
+
+	if looksLikeMETIS(data, eof) {
+		return FormatMETIS, nil
+	}
+	return FormatSNAP, nil
+}
+
+// looksLikeMETIS returns whether the numeric lines data are a METIS header
+// and adjacency lists rather than an edge list. Edge lists consist of lines
+// with two or three fields, while adjacency lists have a line per node of
+// varying length, which is empty for nodes without neighbours.
+func looksLikeMETIS(data []string, eof bool) bool {
+	if _, _, _, err := parseMETISHeader(data[0]); err != nil {
+		return false
+	}
+
+	if len(strings.Fields(data[0])) == 4 {
+		return true
+	}
+
+	if eof {
+		// The whole input is known, so it is METIS if it can be read as such.
+		g, _, err := parseMETIS(bufio.NewScanner(strings.NewReader(strings.Join(data, "\n") + "\n")))
+		return err == nil && len(g.edges) == 2*g.m
+	}
+
+	for _, line := range data[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 3 || !numeric(fields) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// numeric returns whether fields is non-empty and contains only numbers.
+func numeric(fields []string) bool {
+	for _, field := range fields {
+		if _, err := strconv.ParseFloat(field, 64); err != nil {
+			return false
+		}
+	}
+	return len(fields) > 0
+}
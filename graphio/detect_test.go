@@ -0,0 +1,94 @@
+package graphio_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"route-planning/graphio"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte("%%MatrixMarket matrix coordinate pattern symmetric\n2 2 1\n2 1\n"))
+	zw.Close()
+
+	tests := []struct {
+		name     string
+		data     string
+		expected graphio.Format
+	}{
+		{"dimacs", "c 9th DIMACS challenge\nc\np sp 2 1\na 1 2 3\n", graphio.FormatDIMACS},
+		{"mtx", "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 0.5\n", graphio.FormatMTX},
+		{"mtx lower case", "%%matrixmarket matrix coordinate pattern symmetric\n", graphio.FormatMTX},
+		{"gzip", gz.String(), graphio.FormatMTX},
+		{"metis", "% comment\n4 2\n2\n1 3\n2\n\n", graphio.FormatMETIS},
+		{"metis weights", "3 2 001\n2 4\n1 4 3 7\n2 7\n", graphio.FormatMETIS},
+		{"snap", "# Directed graph\n# FromNodeId\tToNodeId\n0\t1\n1\t2\n", graphio.FormatSNAP},
+		{"networkrepository", "% sym unweighted\n% 3 3 2\n1 2\n2 3\n", graphio.FormatSNAP},
+		{"weighted edge list", "1 2 0.5\n2 3 1.5\n3 1 2\n", graphio.FormatSNAP},
+		{"csv", "from,to,cost\n1,2,3\n", graphio.FormatCSV},
+		{"tsv", "from\tto\tcost\n1\t2\t3\n", graphio.FormatTSV},
+		{"osm xml", "<?xml version=\"1.0\"?>\n<osm version=\"0.6\">\n</osm>\n", graphio.FormatOSMXML},
+	}
+
+	for _, test := range tests {
+		format, err := graphio.DetectFormat(writeTestFile(t, "graph", test.data))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if format != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, format)
+		}
+	}
+}
+
+func TestDetectFormatOSMPBF(t *testing.T) {
+	format, err := graphio.DetectFormat(writeTestPBF(t, osmTestCoords, osmTestWays))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if format != graphio.FormatOSMPBF {
+		t.Errorf("expected %s, got %s", graphio.FormatOSMPBF, format)
+	}
+}
+
+func TestDetectFormatUnknown(t *testing.T) {
+	tests := map[string]string{
+		"empty":       "",
+		"comments":    "% only\n% comments\n",
+		"coordinates": "c coordinates\np aux sp co 2\nv 1 1 1\n",
+		"text":        "hello world\n",
+	}
+
+	for name, data := range tests {
+		if _, err := graphio.DetectFormat(writeTestFile(t, "graph", data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDetectFormatFromReader(t *testing.T) {
+	// Large enough to exceed the inspected prefix.
+	data := "p sp 2 1\n" + strings.Repeat("c padding\n", 10000) + "a 1 2 3\n"
+
+	format, r, err := graphio.DetectFormatFromReader(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if format != graphio.FormatDIMACS {
+		t.Errorf("expected %s, got %s", graphio.FormatDIMACS, format)
+	}
+
+	// The returned reader still provides the inspected bytes.
+	read, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(read) != data {
+		t.Errorf("expected the reader to return the whole input")
+	}
+}
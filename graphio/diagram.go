@@ -0,0 +1,249 @@
+package graphio
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"route-planning/graph"
+	"strconv"
+)
+
+// DiagramOutput writes graphs for drawing tools. Edges and nodes of the
+// highlighted path are marked, WritePath only writes the path itself.
+type DiagramOutput interface {
+	GraphWriter
+	PathWriter
+}
+
+// diagram holds the edges to draw and the highlighted path.
+type diagram struct {
+	n     int
+	edges []graph.Edge
+	// undirected is set if every edge has a reverse edge with the same cost.
+	// Only one direction is drawn then.
+	undirected bool
+
+	pathEdges map[[2]graph.Node]bool
+	pathNodes map[graph.Node]bool
+}
+
+func newDiagram(g graph.Graph, highlight []graph.Edge) *diagram {
+	d := &diagram{n: g.N()}
+
+	for v := 0; v < g.N(); v++ {
+		d.edges = append(d.edges, g.OutgoingEdges(graph.Node(v))...)
+	}
+
+	d.undirected = len(d.edges) > 0 && isSymmetric(d.edges)
+	if d.undirected {
+		lower := d.edges[:0]
+		for _, e := range d.edges {
+			if e.From > e.To {
+				lower = append(lower, e)
+			}
+		}
+		d.edges = lower
+	}
+
+	d.highlight(highlight)
+	return d
+}
+
+// pathDiagram returns the diagram of only the path.
+func pathDiagram(path []graph.Edge) *diagram {
+	d := &diagram{edges: path}
+	d.highlight(path)
+
+	for v := range d.pathNodes {
+		if int(v) >= d.n {
+			d.n = int(v) + 1
+		}
+	}
+
+	return d
+}
+
+func (d *diagram) highlight(path []graph.Edge) {
+	d.pathEdges = make(map[[2]graph.Node]bool, len(path))
+	d.pathNodes = make(map[graph.Node]bool, len(path)+1)
+
+	for _, e := range path {
+		d.pathEdges[[2]graph.Node{e.From, e.To}] = true
+		d.pathNodes[e.From] = true
+		d.pathNodes[e.To] = true
+	}
+}
+
+func (d *diagram) onPath(e graph.Edge) bool {
+	if d.pathEdges[[2]graph.Node{e.From, e.To}] {
+		return true
+	}
+	return d.undirected && d.pathEdges[[2]graph.Node{e.To, e.From}]
+}
+
+// nodes returns the nodes to draw, i.e. all nodes of the graph or only those
+// of the path.
+func (d *diagram) nodes(pathOnly bool) []graph.Node {
+	var nodes []graph.Node
+	for v := 0; v < d.n; v++ {
+		if !pathOnly || d.pathNodes[graph.Node(v)] {
+			nodes = append(nodes, graph.Node(v))
+		}
+	}
+	return nodes
+}
+
+type dotOutput struct {
+	highlight []graph.Edge
+}
+
+// NewDOTOutput returns a writer for Graphviz DOT files. Nodes are labelled
+// with their 1-based ID and edges with their cost. The edges of highlight
+// and their nodes are colored.
+func NewDOTOutput(highlight []graph.Edge) DiagramOutput {
+	return dotOutput{highlight: highlight}
+}
+
+func (do dotOutput) WriteGraph(w io.Writer, g graph.Graph) error {
+	return writeDOT(w, newDiagram(g, do.highlight), false)
+}
+
+func (do dotOutput) WritePath(w io.Writer, path []graph.Edge) error {
+	return writeDOT(w, pathDiagram(path), true)
+}
+
+const (
+	dotPathColor = "red"
+	dotPathWidth = 2.5
+)
+
+func writeDOT(w io.Writer, d *diagram, pathOnly bool) error {
+	bw := bufio.NewWriter(w)
+
+	kind, arrow := "digraph", "->"
+	if d.undirected {
+		kind, arrow = "graph", "--"
+	}
+
+	fmt.Fprintf(bw, "%s G {\n", kind)
+	fmt.Fprintln(bw, "\tnode [shape=circle];")
+
+	for _, v := range d.nodes(pathOnly) {
+		if d.pathNodes[v] {
+			fmt.Fprintf(bw, "\t%d [color=%s, penwidth=%g];\n", v+1, dotPathColor, dotPathWidth)
+		} else {
+			fmt.Fprintf(bw, "\t%d;\n", v+1)
+		}
+	}
+
+	for _, e := range d.edges {
+		cost := strconv.FormatFloat(e.Cost, 'g', -1, 64)
+		if d.onPath(e) {
+			fmt.Fprintf(bw, "\t%d %s %d [label=%q, color=%s, fontcolor=%s, penwidth=%g];\n",
+				e.From+1, arrow, e.To+1, cost, dotPathColor, dotPathColor, dotPathWidth)
+		} else {
+			fmt.Fprintf(bw, "\t%d %s %d [label=%q];\n", e.From+1, arrow, e.To+1, cost)
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+type graphMLOutput struct {
+	highlight []graph.Edge
+}
+
+// NewGraphMLOutput returns a writer for GraphML files. Edges carry their cost
+// and all elements a boolean path attribute marking the edges of highlight
+// and their nodes.
+func NewGraphMLOutput(highlight []graph.Edge) DiagramOutput {
+	return graphMLOutput{highlight: highlight}
+}
+
+func (gmo graphMLOutput) WriteGraph(w io.Writer, g graph.Graph) error {
+	return writeGraphML(w, newDiagram(g, gmo.highlight), false)
+}
+
+func (gmo graphMLOutput) WritePath(w io.Writer, path []graph.Edge) error {
+	return writeGraphML(w, pathDiagram(path), true)
+}
+
+type graphMLKey struct {
+	ID      string `xml:"id,attr"`
+	For     string `xml:"for,attr"`
+	Name    string `xml:"attr.name,attr"`
+	Type    string `xml:"attr.type,attr"`
+	Default string `xml:"default,omitempty"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLFile struct {
+	XMLName xml.Name     `xml:"http://graphml.graphdrawing.org/xmlns graphml"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+func writeGraphML(w io.Writer, d *diagram, pathOnly bool) error {
+	file := graphMLFile{
+		Keys: []graphMLKey{
+			{ID: "cost", For: "edge", Name: "cost", Type: "double"},
+			{ID: "path", For: "edge", Name: "path", Type: "boolean", Default: "false"},
+			{ID: "nodepath", For: "node", Name: "path", Type: "boolean", Default: "false"},
+		},
+	}
+
+	file.Graph.ID = "G"
+	file.Graph.EdgeDefault = "directed"
+	if d.undirected {
+		file.Graph.EdgeDefault = "undirected"
+	}
+
+	for _, v := range d.nodes(pathOnly) {
+		node := graphMLNode{ID: graphMLNodeID(v)}
+		if d.pathNodes[v] {
+			node.Data = []graphMLData{{Key: "nodepath", Value: "true"}}
+		}
+		file.Graph.Nodes = append(file.Graph.Nodes, node)
+	}
+
+	for _, e := range d.edges {
+		edge := graphMLEdge{
+			Source: graphMLNodeID(e.From),
+			Target: graphMLNodeID(e.To),
+			Data:   []graphMLData{{Key: "cost", Value: strconv.FormatFloat(e.Cost, 'g', -1, 64)}},
+		}
+		if d.onPath(e) {
+			edge.Data = append(edge.Data, graphMLData{Key: "path", Value: "true"})
+		}
+		file.Graph.Edges = append(file.Graph.Edges, edge)
+	}
+
+	return writeXML(w, file)
+}
+
+func graphMLNodeID(v graph.Node) string {
+	return "n" + strconv.Itoa(int(v)+1)
+}
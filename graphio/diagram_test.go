@@ -0,0 +1,109 @@
+package graphio_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"strings"
+	"testing"
+)
+
+var diagramTestEdges = []graph.Edge{{From: 0, To: 1, Cost: 1}, {From: 1, To: 2, Cost: 2.5}, {From: 2, To: 0, Cost: 4}}
+
+func TestDOTOutput(t *testing.T) {
+	g := graph.NewAdjacencyList(diagramTestEdges, 3)
+
+	var buf bytes.Buffer
+	if err := graphio.NewDOTOutput(diagramTestEdges[:1]).WriteGraph(&buf, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `digraph G {
+	node [shape=circle];
+	1 [color=red, penwidth=2.5];
+	2 [color=red, penwidth=2.5];
+	3;
+	1 -> 2 [label="1", color=red, fontcolor=red, penwidth=2.5];
+	2 -> 3 [label="2.5"];
+	3 -> 1 [label="4"];
+}
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
+
+func TestDOTOutputUndirected(t *testing.T) {
+	g := newUndirected(t, diagramTestEdges, 3)
+
+	// The path traverses the edge against the direction it is drawn in.
+	var buf bytes.Buffer
+	if err := graphio.NewDOTOutput([]graph.Edge{{From: 0, To: 2, Cost: 4}}).WriteGraph(&buf, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "graph G {") || strings.Count(out, " -- ") != 3 {
+		t.Errorf("expected an undirected graph with 3 edges, got\n%s", out)
+	}
+	if !strings.Contains(out, `3 -- 1 [label="4", color=red`) {
+		t.Errorf("expected the path edge to be highlighted, got\n%s", out)
+	}
+}
+
+func TestGraphMLOutput(t *testing.T) {
+	g := graph.NewAdjacencyList(diagramTestEdges, 4)
+
+	var buf bytes.Buffer
+	if err := graphio.NewGraphMLOutput(diagramTestEdges[1:2]).WriteGraph(&buf, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type data struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+	var graphML struct {
+		Graph struct {
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID   string `xml:"id,attr"`
+				Data []data `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Data   []data `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &graphML); err != nil {
+		t.Fatalf("invalid GraphML: %v\n%s", err, buf.String())
+	}
+
+	if graphML.Graph.EdgeDefault != "directed" || len(graphML.Graph.Nodes) != 4 || len(graphML.Graph.Edges) != 3 {
+		t.Fatalf("expected a directed graph with 4 nodes and 3 edges, got\n%s", buf.String())
+	}
+
+	edge := graphML.Graph.Edges[1]
+	if edge.Source != "n2" || edge.Target != "n3" || len(edge.Data) != 2 ||
+		edge.Data[0] != (data{"cost", "2.5"}) || edge.Data[1] != (data{"path", "true"}) {
+		t.Errorf("unexpected path edge %+v", edge)
+	}
+
+	if nodes := graphML.Graph.Nodes; len(nodes[0].Data) != 0 || len(nodes[1].Data) != 1 {
+		t.Errorf("expected only nodes of the path to be marked, got %+v", nodes)
+	}
+}
+
+func TestDOTOutputWritePath(t *testing.T) {
+	var buf bytes.Buffer
+	if err := graphio.NewDOTOutput(nil).WritePath(&buf, diagramTestEdges[1:]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out := buf.String(); strings.Count(out, "->") != 2 || strings.Contains(out, "\t1 -> 2") {
+		t.Errorf("expected only the path edges, got\n%s", out)
+	}
+}
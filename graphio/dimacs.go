@@ -2,109 +2,288 @@ package graphio
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
-	"os"
+	"io"
+	"math"
 	"route-planning/graph"
 	"strconv"
 	"strings"
 )
 
 type dimacsInput struct {
-	file string
+	src     *source
+	opts    parseOptions
+	skipped []*ParseError
 }
 
-func NewDIMANCSInput(file string) GraphInput {
-	return &dimacsInput{file: file}
+// NewDIMANCSInput reads a DIMACS .gr file. The arc lines are parsed in
+// parallel, see WithWorkers, and malformed ones can be skipped, see
+// WithLenient.
+func NewDIMANCSInput(file string, opts ...ParseOption) GraphInput {
+	return &dimacsInput{src: fileSource(file), opts: newParseOptions(opts)}
 }
 
-func (di dimacsInput) LoadGraph() ([]graph.Edge, int, error) {
-	f, err := os.Open(di.file)
+// NewDIMACSInputFromReader reads a DIMACS graph from r. The graph can only be
+// loaded once.
+func NewDIMACSInputFromReader(r io.Reader, opts ...ParseOption) GraphInput {
+	return &dimacsInput{src: readerSource(r), opts: newParseOptions(opts)}
+}
+
+func (di *dimacsInput) LoadGraph() ([]graph.Edge, int, error) {
+	var edges []graph.Edge
+
+	n, _, err := di.parse(false,
+		func(n, m int) { edges = make([]graph.Edge, 0, preallocated(m)) },
+		func(e graph.Edge, line int) error {
+			edges = append(edges, e)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return edges, n, nil
+}
+
+func (di *dimacsInput) loadForValidation() (*validationGraph, error) {
+	g := &validationGraph{file: di.src.String()}
+
+	n, m, err := di.parse(false,
+		func(n, m int) {
+			g.edges = make([]graph.Edge, 0, preallocated(m))
+			g.lines = make([]int, 0, preallocated(m))
+		},
+		func(e graph.Edge, line int) error {
+			g.edges = append(g.edges, e)
+			g.lines = append(g.lines, line)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	g.n, g.m = n, m
+	return g, nil
+}
+
+// StreamEdges calls visit for every edge without keeping them in memory.
+func (di *dimacsInput) StreamEdges(nodes func(n int), visit func(graph.Edge) error) (int, error) {
+	return di.streamEdgeLines(nodes, func(e graph.Edge, line int) error { return visit(e) })
+}
+
+func (di *dimacsInput) streamEdgeLines(nodes func(n int), visit func(e graph.Edge, line int) error) (int, error) {
+	n, _, err := di.parse(true, func(n, m int) { nodes(n) }, visit)
+	return n, err
+}
+
+func (di *dimacsInput) file() string {
+	return di.src.String()
+}
+
+func (di *dimacsInput) rereadable() bool {
+	return di.src.rereadable()
+}
+
+func (di *dimacsInput) SkippedLines() []*ParseError {
+	return di.skipped
+}
+
+// parse calls header with the number of nodes and edges declared in the
+// problem line and visit for every edge and its line. It returns the declared numbers. If
+// checkNodes is set, arcs with nodes outside of the declared range are
+// malformed.
+func (di *dimacsInput) parse(checkNodes bool, header func(n, m int), visit func(e graph.Edge, line int) error) (int, int, error) {
+	errs := &lineErrors{src: di.src, lenient: di.opts.lenient}
+	n, m, err := di.read(errs, checkNodes, header, visit)
+	di.skipped = errs.skipped
+	return n, m, err
+}
+
+func (di *dimacsInput) read(errs *lineErrors, checkNodes bool, header func(n, m int), visit func(e graph.Edge, line int) error) (int, int, error) {
+	f, err := di.src.open()
 	if err != nil {
-		return nil, 0, fmt.Errorf("error opening graph file %s: %w", di.file, err)
+		return 0, 0, fmt.Errorf("error opening graph file %s: %w", di.src, err)
 	}
 	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
+	r := bufio.NewReader(f)
 
-	n, m, err := parseProblemLine(scanner)
+	problemLine, line, err := readRelevantLine(r, 1)
 	if err != nil {
-		return nil, 0, fmt.Errorf("DIMACS parsing failed: \n\t%w", err)
+		return 0, 0, fmt.Errorf("error reading graph file %s: %w", di.src, err)
 	}
 
-	edges, err := parseArcDescriptorLines(scanner, m)
+	n, m, err := parseProblemLine(problemLine, line)
 	if err != nil {
-		return nil, 0, fmt.Errorf("DIMACS parsing failed: \n\t%w", err)
+		return 0, 0, fmt.Errorf("DIMACS parsing failed: \n\t%w", inFile(err, di.src))
 	}
 
-	return edges, n, nil
+	header(n, m)
+
+	nodes := uncheckedNodes
+	if checkNodes {
+		nodes = n
+	}
+
+	err = parseChunks(r, line+1, di.opts, errs, arcParser(nodes), func(value interface{}) error {
+		arcs := value.(*arcChunk)
+		for i, e := range arcs.edges {
+			if err := visit(e, arcs.lines[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("DIMACS parsing failed: \n\t%w", err)
+	}
+
+	return n, m, nil
 }
 
-func parseProblemLine(scanner *bufio.Scanner) (int, int, error) {
-	problemLine := nextRelevantLine(scanner)
-	if problemLine[0] != 'p' {
-		return 0, 0, fmt.Errorf("expected problem line but got %q", problemLine)
+// readRelevantLine returns the next line which is neither empty nor a
+// comment and its number, counting from line.
+func readRelevantLine(r *bufio.Reader, line int) (string, int, error) {
+	for ; ; line++ {
+		text, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", line, err
+		}
+
+		text = strings.TrimRight(text, "\r\n")
+		if !checkEmpty(text) && text[0] != 'c' {
+			return text, line, nil
+		}
+
+		if err == io.EOF {
+			return "", line, nil
+		}
+	}
+}
+
+// parseProblemLine parses the problem line with the given line number.
+func parseProblemLine(problemLine string, line int) (int, int, error) {
+	if problemLine == "" || problemLine[0] != 'p' {
+		return 0, 0, parseErrorf(line, 0, "expected problem line but got %q", problemLine)
 	}
 
 	fields := strings.Split(problemLine, " ")
 	if len(fields) != 4 || fields[1] != "sp" {
-		return 0, 0, fmt.Errorf("expected problem line of format 'p sp {n} {m}', but got %s", problemLine)
+		return 0, 0, parseErrorf(line, 0, "expected problem line of format 'p sp {n} {m}', but got %s", problemLine)
 	}
 
 	n, err := strconv.Atoi(fields[2])
 	if err != nil {
-		return 0, 0, fmt.Errorf("error parsing {n} in problem line: %w", err)
+		return 0, 0, parseErrorf(line, splitColumn(fields, 2), "error parsing {n} in problem line: %w", err)
+	}
+	if n < 0 {
+		return 0, 0, parseErrorf(line, splitColumn(fields, 2), "negative {n} %d in problem line", n)
 	}
 
 	m, err := strconv.Atoi(fields[3])
 	if err != nil {
-		return 0, 0, fmt.Errorf("error parsing {m} in problem line: %w", err)
+		return 0, 0, parseErrorf(line, splitColumn(fields, 3), "error parsing {m} in problem line: %w", err)
+	}
+	if m < 0 {
+		return 0, 0, parseErrorf(line, splitColumn(fields, 3), "negative {m} %d in problem line", m)
 	}
 
 	return n, m, nil
 }
 
-func parseArcDescriptorLines(scanner *bufio.Scanner, m int) ([]graph.Edge, error) {
-	line := nextRelevantLine(scanner)
+// uncheckedNodes disables the check of the nodes of arcs and data lines
+// against the number of nodes declared in the header.
+const uncheckedNodes = -1
 
-	edges := make([]graph.Edge, 0, 2*m)
+// arcChunk holds the arcs of a chunk and their lines.
+type arcChunk struct {
+	edges []graph.Edge
+	lines []int
+}
 
-	for line != "" {
+// arcParser returns a chunkParser parsing chunks of arc lines into an
+// *arcChunk. Unless n is uncheckedNodes, arcs with nodes outside of [1, n]
+// are malformed.
+func arcParser(n int) chunkParser {
+	return func(data []byte, line int, errs *lineErrors) (interface{}, error) {
+		count := bytes.Count(data, []byte{'\n'}) + 1
+		arcs := &arcChunk{edges: make([]graph.Edge, 0, count), lines: make([]int, 0, count)}
 
-		if line[0] != 'a' {
-			return nil, fmt.Errorf("expected arc descriptor line but got %q", line)
-		}
+		err := forEachLine(data, line, func(text []byte, line int) error {
+			arc := string(bytes.TrimRight(text, "\r"))
+			if checkEmpty(arc) || arc[0] == 'c' {
+				return nil
+			}
 
-		fields := strings.Split(line, " ")
+			e, err := parseArcDescriptorLine(arc, line, n)
+			if err != nil {
+				return errs.skip(err)
+			}
 
-		if len(fields) != 4 {
-			return nil, fmt.Errorf("expected arc descriptor line to have format 'a {u} {v} {w}' but got %q", line)
-		}
+			arcs.edges = append(arcs.edges, e)
+			arcs.lines = append(arcs.lines, line)
+			return nil
+		})
 
-		u, err := strconv.Atoi(fields[1])
-		if err != nil {
-			return nil, fmt.Errorf("error parsing {u} of arc line %q: %w", line, err)
-		}
+		return arcs, err
+	}
+}
 
-		v, err := strconv.Atoi(fields[2])
-		if err != nil {
-			return nil, fmt.Errorf("error parsing {v} of arc line %q: %w", line, err)
-		}
+// parseArcDescriptorLine parses the arc line with the given line number. Unless
+// n is uncheckedNodes, its nodes have to be in [1, n].
+func parseArcDescriptorLine(arc string, line int, n int) (graph.Edge, error) {
+	if arc[0] != 'a' {
+		return graph.Edge{}, parseErrorf(line, 0, "expected arc descriptor line but got %q", arc)
+	}
 
-		w, err := strconv.Atoi(fields[3])
-		if err != nil {
-			return nil, fmt.Errorf("error parsing {w} of arc line %q: %w", line, err)
-		}
+	fields := strings.Split(arc, " ")
 
-		edges = append(edges, graph.Edge{
-			From: graph.Node(u - 1),
-			To:   graph.Node(v - 1),
-			Cost: float64(w),
-		})
+	if len(fields) != 4 {
+		return graph.Edge{}, parseErrorf(line, 0, "expected arc descriptor line to have format 'a {u} {v} {w}' but got %q", arc)
+	}
 
-		line = nextRelevantLine(scanner)
+	u, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return graph.Edge{}, parseErrorf(line, splitColumn(fields, 1), "error parsing {u} of arc line %q: %w", arc, err)
 	}
 
-	return edges, nil
+	v, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return graph.Edge{}, parseErrorf(line, splitColumn(fields, 2), "error parsing {v} of arc line %q: %w", arc, err)
+	}
+
+	if n != uncheckedNodes {
+		for i, node := range []int{u, v} {
+			if node < 1 || node > n {
+				return graph.Edge{}, parseErrorf(line, splitColumn(fields, i+1), "node %d of arc line %q out of range [1, %d]", node, arc, n)
+			}
+		}
+	}
+
+	w, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return graph.Edge{}, parseErrorf(line, splitColumn(fields, 3), "error parsing {w} of arc line %q: %w", arc, err)
+	}
+
+	return graph.Edge{
+		From: graph.Node(u - 1),
+		To:   graph.Node(v - 1),
+		Cost: float64(w),
+	}, nil
+}
+
+// splitColumn returns the 1-based column of fields[i] in the line split into
+// fields at single spaces. It is only computed for errors to keep splitting
+// arc lines cheap.
+func splitColumn(fields []string, i int) int {
+	column := 1
+	for _, f := range fields[:i] {
+		column += len(f) + 1
+	}
+	return column
 }
 
 // nextRelevantLine returns the next relevant line while skipping empty lines and comments.
@@ -122,3 +301,34 @@ func checkEmpty(line string) bool {
 	l := strings.Replace(line, " ", "", -1)
 	return l == ""
 }
+
+type dimacsOutput struct{}
+
+// NewDIMACSOutput returns a writer for DIMACS .gr files. The format only
+// allows integer costs.
+func NewDIMACSOutput() GraphWriter {
+	return dimacsOutput{}
+}
+
+func (dimacsOutput) WriteGraph(w io.Writer, g graph.Graph) error {
+	m := 0
+	for v := 0; v < g.N(); v++ {
+		for _, e := range g.OutgoingEdges(graph.Node(v)) {
+			if e.Cost != math.Trunc(e.Cost) || math.IsInf(e.Cost, 0) {
+				return fmt.Errorf("DIMACS requires integer costs but edge %d -> %d has cost %g", e.From+1, e.To+1, e.Cost)
+			}
+			m++
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "p sp %d %d\n", g.N(), m)
+
+	for v := 0; v < g.N(); v++ {
+		for _, e := range g.OutgoingEdges(graph.Node(v)) {
+			fmt.Fprintf(bw, "a %d %d %d\n", e.From+1, e.To+1, int64(e.Cost))
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,39 @@
+package graphio_test
+
+import (
+	"bytes"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"testing"
+)
+
+func TestDIMACSOutput(t *testing.T) {
+	edges := []graph.Edge{{From: 0, To: 1, Cost: 3}, {From: 1, To: 2, Cost: 4}, {From: 2, To: 0, Cost: 0}}
+
+	var buf bytes.Buffer
+	if err := graphio.NewDIMACSOutput().WriteGraph(&buf, graph.NewAdjacencyList(edges, 3)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := "p sp 3 3\na 1 2 3\na 2 3 4\na 3 1 0\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	got, n, err := graphio.NewDIMACSInputFromReader(&buf).LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error reading the written graph: %v", err)
+	}
+	if n != 3 || !reflect.DeepEqual(got, edges) {
+		t.Errorf("expected 3 nodes and %v, got %d nodes and %v", edges, n, got)
+	}
+}
+
+func TestDIMACSOutputRejectsFractionalCosts(t *testing.T) {
+	g := graph.NewAdjacencyList([]graph.Edge{{From: 0, To: 1, Cost: 1.5}}, 2)
+
+	var buf bytes.Buffer
+	if err := graphio.NewDIMACSOutput().WriteGraph(&buf, g); err == nil {
+		t.Error("expected an error for a fractional cost")
+	}
+}
@@ -0,0 +1,120 @@
+package graphio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+)
+
+// ParseError describes malformed data at a position of an input.
+type ParseError struct {
+	// File is the name of the file or "<reader>" for inputs read from an
+	// io.Reader.
+	File string
+	// Line is the 1-based line number.
+	Line int
+	// Column is the 1-based byte offset of the malformed field in the line or
+	// 0 if the line as a whole is malformed.
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseErrorf returns a ParseError without file name, which is added by
+// lineErrors.skip or inFile.
+func parseErrorf(line, column int, format string, args ...interface{}) *ParseError {
+	return &ParseError{Line: line, Column: column, Err: fmt.Errorf(format, args...)}
+}
+
+// LenientInput is implemented by inputs accepting WithLenient.
+type LenientInput interface {
+	// SkippedLines returns the errors of the lines skipped by the last load
+	// in input order.
+	SkippedLines() []*ParseError
+}
+
+// lineErrors handles the errors of malformed lines of src. They abort
+// parsing unless lenient is set, in which case the lines are skipped and
+// their errors collected.
+type lineErrors struct {
+	src     *source
+	lenient bool
+	skipped []*ParseError
+}
+
+// skip returns nil if err is a ParseError which was recorded as skipped and
+// err otherwise.
+func (le *lineErrors) skip(err error) error {
+	err = inFile(err, le.src)
+
+	var pe *ParseError
+	if !le.lenient || !errors.As(err, &pe) {
+		return err
+	}
+
+	le.skipped = append(le.skipped, pe)
+	return nil
+}
+
+// inFile sets the file name of the ParseError in err and returns err. It has
+// to be called before wrapping err, which formats its message.
+func inFile(err error, src *source) error {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		pe.File = src.String()
+	}
+	return err
+}
+
+// field is a field of a line and its 1-based column.
+type field struct {
+	text   string
+	column int
+}
+
+// splitFields splits line around each run of characters satisfying isSep
+// like strings.FieldsFunc and keeps the column of every field.
+func splitFields(line string, isSep func(rune) bool) []field {
+	var fields []field
+
+	start := -1
+	for i, r := range line {
+		switch {
+		case isSep(r) && start >= 0:
+			fields = append(fields, field{text: line[start:i], column: start + 1})
+			start = -1
+		case !isSep(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, field{text: line[start:], column: start + 1})
+	}
+
+	return fields
+}
+
+// lineScanner is a bufio.Scanner counting the lines read.
+type lineScanner struct {
+	*bufio.Scanner
+	// line is the number of the line last read.
+	line int
+}
+
+func (s *lineScanner) Scan() bool {
+	if !s.Scanner.Scan() {
+		return false
+	}
+	s.line++
+	return true
+}
@@ -0,0 +1,210 @@
+package graphio_test
+
+import (
+	"errors"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"testing"
+)
+
+func TestParseErrorPosition(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		load         func(file string) error
+		line, column int
+	}{
+		{
+			name: "dimacs",
+			data: "c comment\np sp 3 2\na 1 2 3\na 2 x 4\n",
+			load: func(file string) error {
+				_, _, err := graphio.NewDIMANCSInput(file).LoadGraph()
+				return err
+			},
+			line: 4, column: 5,
+		},
+		{
+			name: "dimacs problem line",
+			data: "p sp 3 many\n",
+			load: func(file string) error {
+				_, _, err := graphio.NewDIMANCSInput(file).LoadGraph()
+				return err
+			},
+			line: 1, column: 8,
+		},
+		{
+			name: "mtx",
+			data: "%%MatrixMarket matrix coordinate real general\n% comment\n3 3 2\n1 2 1.5\n2 3 y\n",
+			load: func(file string) error {
+				_, _, err := graphio.NewMTXInput(file).LoadGraph()
+				return err
+			},
+			line: 5, column: 5,
+		},
+		{
+			name: "snap",
+			data: "# comment\n1 2\n2 3 heavy\n",
+			load: func(file string) error {
+				_, _, err := graphio.NewSNAPInput(file, graphio.SNAPOptions{}).LoadGraph()
+				return err
+			},
+			line: 3, column: 5,
+		},
+		{
+			name: "csv",
+			data: "from,to,cost\n1,2,3\n2,3,-\n",
+			load: func(file string) error {
+				opts := graphio.DefaultCSVOptions
+				opts.Header = true
+				_, _, err := graphio.NewCSVInput(file, opts).LoadGraph()
+				return err
+			},
+			line: 3, column: 5,
+		},
+		{
+			name: "metis",
+			data: "% comment\n3 2\n2\n1 4\n2\n",
+			load: func(file string) error {
+				_, _, err := graphio.NewMETISInput(file).LoadGraph()
+				return err
+			},
+			line: 4, column: 3,
+		},
+	}
+
+	for _, test := range tests {
+		file := writeTestFile(t, "graph", test.data)
+
+		var pe *graphio.ParseError
+		if err := test.load(file); !errors.As(err, &pe) {
+			t.Errorf("%s: expected a ParseError, got %v", test.name, err)
+			continue
+		}
+
+		if pe.File != file || pe.Line != test.line || pe.Column != test.column {
+			t.Errorf("%s: expected error at %s:%d:%d, got %v", test.name, file, test.line, test.column, pe)
+		}
+	}
+}
+
+func TestLenientInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		in       func(file string) graphio.GraphInput
+		expected []graph.Edge
+		n        int
+		lines    []int
+	}{
+		{
+			name: "dimacs",
+			data: "p sp 3 4\na 1 2 1\na 2 3 x\nb\na 3 1 2\n",
+			in: func(file string) graphio.GraphInput {
+				return graphio.NewDIMANCSInput(file, graphio.WithLenient(), graphio.WithWorkers(3), graphio.WithChunkSize(16))
+			},
+			expected: []graph.Edge{{From: 0, To: 1, Cost: 1}, {From: 2, To: 0, Cost: 2}},
+			n:        3,
+			lines:    []int{3, 4},
+		},
+		{
+			name: "mtx",
+			data: "%%MatrixMarket matrix coordinate integer general\n3 3 3\n1 2 1\n2 3\n3 1 2\n",
+			in: func(file string) graphio.GraphInput {
+				return graphio.NewMTXInput(file, graphio.WithLenient())
+			},
+			expected: []graph.Edge{{From: 0, To: 1, Cost: 1}, {From: 2, To: 0, Cost: 2}},
+			n:        3,
+			lines:    []int{4},
+		},
+		{
+			name: "snap",
+			data: "0 1\n1\n1 2 x\n2 0 -\n2 0 2\n9 -1\n",
+			in: func(file string) graphio.GraphInput {
+				return graphio.NewSNAPInput(file, graphio.SNAPOptions{Directed: true}, graphio.WithLenient(), graphio.WithWorkers(3), graphio.WithChunkSize(16))
+			},
+			expected: []graph.Edge{{From: 0, To: 1, Cost: 1}, {From: 2, To: 0, Cost: 2}},
+			n:        3,
+			lines:    []int{2, 3, 4, 6},
+		},
+		{
+			name: "csv",
+			data: "0,1,1\n1,9,x\n2,\"0\"x,1\n2,0,2\n",
+			in: func(file string) graphio.GraphInput {
+				return graphio.NewCSVInput(file, graphio.DefaultCSVOptions, graphio.WithLenient())
+			},
+			expected: []graph.Edge{{From: 0, To: 1, Cost: 1}, {From: 2, To: 0, Cost: 2}},
+			n:        3,
+			lines:    []int{2, 3},
+		},
+		{
+			name: "csv string IDs",
+			data: "a,b,1\nc,d,x\nb,a,2\n",
+			in: func(file string) graphio.GraphInput {
+				opts := graphio.DefaultCSVOptions
+				opts.StringIDs = true
+				return graphio.NewCSVInput(file, opts, graphio.WithLenient())
+			},
+			expected: []graph.Edge{{From: 0, To: 1, Cost: 1}, {From: 1, To: 0, Cost: 2}},
+			n:        2,
+			lines:    []int{2},
+		},
+	}
+
+	for _, test := range tests {
+		in := test.in(writeTestFile(t, "graph", test.data))
+
+		edges, n, err := in.LoadGraph()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(edges, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, edges)
+		}
+		// Skipped lines do not add nodes.
+		if n != test.n {
+			t.Errorf("%s: expected %d nodes, got %d", test.name, test.n, n)
+		}
+
+		var lines []int
+		for _, pe := range in.(graphio.LenientInput).SkippedLines() {
+			lines = append(lines, pe.Line)
+		}
+		if !reflect.DeepEqual(lines, test.lines) {
+			t.Errorf("%s: expected skipped lines %v, got %v", test.name, test.lines, lines)
+		}
+	}
+}
+
+func TestLenientInputKeepsHeaderErrors(t *testing.T) {
+	file := writeTestFile(t, "graph.gr", "p sp x 1\na 1 2 3\n")
+
+	if _, _, err := graphio.NewDIMANCSInput(file, graphio.WithLenient()).LoadGraph(); err == nil {
+		t.Error("expected an error for the malformed problem line")
+	}
+}
+
+func TestMTXInputMalformedLines(t *testing.T) {
+	tests := map[string]string{
+		"short size line":      "%%MatrixMarket matrix coordinate pattern general\n3 3\n",
+		"empty line in header": "%%MatrixMarket matrix coordinate pattern general\n%\n\n",
+	}
+
+	for name, data := range tests {
+		_, _, err := graphio.NewMTXInput(writeTestFile(t, "graph.mtx", data)).LoadGraph()
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// Empty lines between the entries are ignored.
+	data := "%%MatrixMarket matrix coordinate pattern general\n\n3 3 2\n1 2\n\n2 3\n"
+	edges, _, err := graphio.NewMTXInput(writeTestFile(t, "graph.mtx", data)).LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(edges) != 2 {
+		t.Errorf("expected 2 edges, got %v", edges)
+	}
+}
@@ -0,0 +1,8 @@
+package graphio
+
+// WithChunkSize sets the size of the chunks parsed in parallel.
+func WithChunkSize(size int) ParseOption {
+	return func(o *parseOptions) {
+		o.chunkSize = size
+	}
+}
@@ -0,0 +1,206 @@
+package graphio
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"route-planning/graph"
+)
+
+// GeoJSONOutput writes GeoJSON FeatureCollections. Nodes are referenced by
+// their 1-based ID as in the input files.
+type GeoJSONOutput interface {
+	GraphOutput
+	GraphWriter
+	PathWriter
+
+	// WriteNodes writes a point for every node, e.g. the settled nodes of a
+	// search. cost is indexed by node and may be nil.
+	WriteNodes(w io.Writer, nodes []graph.Node, cost []float64) error
+}
+
+type geoJSONOutput struct {
+	coords []graph.Coordinate
+}
+
+// NewGeoJSONOutput returns an output placing the nodes at coords.
+func NewGeoJSONOutput(coords []graph.Coordinate) GeoJSONOutput {
+	return &geoJSONOutput{coords: coords}
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+func (gjo geoJSONOutput) position(v graph.Node) [2]float64 {
+	c := gjo.coords[v]
+	return [2]float64{c.Lon, c.Lat}
+}
+
+func (gjo geoJSONOutput) nodeFeature(v graph.Node, properties map[string]interface{}) geoJSONFeature {
+	properties["node"] = v + 1
+	return geoJSONFeature{
+		Type:       "Feature",
+		Geometry:   geoJSONGeometry{Type: "Point", Coordinates: gjo.position(v)},
+		Properties: properties,
+	}
+}
+
+func (gjo geoJSONOutput) edgeFeature(e graph.Edge) geoJSONFeature {
+	return geoJSONFeature{
+		Type: "Feature",
+		Geometry: geoJSONGeometry{
+			Type:        "LineString",
+			Coordinates: [][2]float64{gjo.position(e.From), gjo.position(e.To)},
+		},
+		Properties: map[string]interface{}{
+			"from": e.From + 1,
+			"to":   e.To + 1,
+			"cost": jsonFloat(e.Cost),
+		},
+	}
+}
+
+func (gjo geoJSONOutput) PrintNode(v graph.Node) string {
+	return mustMarshal(gjo.nodeFeature(v, map[string]interface{}{}))
+}
+
+func (gjo geoJSONOutput) PrintEdge(e graph.Edge) string {
+	return mustMarshal(gjo.edgeFeature(e))
+}
+
+// WritePath writes the path as a single line with its total cost and number
+// of hops followed by a point for every node with the cost to reach it.
+func (gjo geoJSONOutput) WritePath(w io.Writer, path []graph.Edge) error {
+	fc := newFeatureCollectionWriter(w)
+
+	if len(path) > 0 {
+		line := [][2]float64{gjo.position(path[0].From)}
+		cost := 0.0
+		for _, e := range path {
+			line = append(line, gjo.position(e.To))
+			cost += e.Cost
+		}
+
+		fc.write(geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONGeometry{Type: "LineString", Coordinates: line},
+			Properties: map[string]interface{}{
+				"source": path[0].From + 1,
+				"target": path[len(path)-1].To + 1,
+				"cost":   jsonFloat(cost),
+				"hops":   len(path),
+			},
+		})
+
+		fc.write(gjo.nodeFeature(path[0].From, map[string]interface{}{"hop": 0, "cost": 0.0}))
+		cost = 0.0
+		for i, e := range path {
+			cost += e.Cost
+			fc.write(gjo.nodeFeature(e.To, map[string]interface{}{"hop": i + 1, "cost": jsonFloat(cost)}))
+		}
+	}
+
+	return fc.close()
+}
+
+func (gjo geoJSONOutput) WriteNodes(w io.Writer, nodes []graph.Node, cost []float64) error {
+	fc := newFeatureCollectionWriter(w)
+
+	for _, v := range nodes {
+		properties := map[string]interface{}{}
+		if cost != nil {
+			properties["cost"] = jsonFloat(cost[v])
+		}
+		fc.write(gjo.nodeFeature(v, properties))
+	}
+
+	return fc.close()
+}
+
+// WriteGraph writes a line for every edge of g.
+func (gjo geoJSONOutput) WriteGraph(w io.Writer, g graph.Graph) error {
+	if err := MatchCoordinates(gjo.coords, g.N()); err != nil {
+		return err
+	}
+
+	fc := newFeatureCollectionWriter(w)
+
+	for i := 0; i < g.N(); i++ {
+		for _, e := range g.OutgoingEdges(graph.Node(i)) {
+			fc.write(gjo.edgeFeature(e))
+		}
+	}
+
+	return fc.close()
+}
+
+// featureCollectionWriter streams features into a FeatureCollection so that
+// large graphs never have to be held in memory as a whole.
+type featureCollectionWriter struct {
+	w     *bufio.Writer
+	count int
+	err   error
+}
+
+func newFeatureCollectionWriter(w io.Writer) *featureCollectionWriter {
+	fc := &featureCollectionWriter{w: bufio.NewWriter(w)}
+	_, fc.err = fc.w.WriteString(`{"type":"FeatureCollection","features":[`)
+	return fc
+}
+
+func (fc *featureCollectionWriter) write(f geoJSONFeature) {
+	if fc.err != nil {
+		return
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		fc.err = err
+		return
+	}
+
+	if fc.count > 0 {
+		fc.w.WriteByte(',')
+	}
+	fc.w.WriteByte('\n')
+	_, fc.err = fc.w.Write(data)
+	fc.count++
+}
+
+func (fc *featureCollectionWriter) close() error {
+	if fc.err != nil {
+		return fmt.Errorf("error writing GeoJSON: %w", fc.err)
+	}
+
+	if _, err := fc.w.WriteString("\n]}\n"); err != nil {
+		return fmt.Errorf("error writing GeoJSON: %w", err)
+	}
+
+	return fc.w.Flush()
+}
+
+// jsonFloat returns f or nil if f cannot be represented in JSON.
+func jsonFloat(f float64) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	return f
+}
+
+func mustMarshal(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
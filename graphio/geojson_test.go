@@ -0,0 +1,89 @@
+package graphio_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"testing"
+)
+
+type testFeatureCollection struct {
+	Type     string
+	Features []struct {
+		Geometry struct {
+			Type        string
+			Coordinates json.RawMessage
+		}
+		Properties map[string]interface{}
+	}
+}
+
+func decodeFeatureCollection(t *testing.T, data []byte) testFeatureCollection {
+	var fc testFeatureCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		t.Fatalf("invalid GeoJSON: %v\n%s", err, data)
+	}
+	if fc.Type != "FeatureCollection" {
+		t.Fatalf("expected a FeatureCollection, got %q", fc.Type)
+	}
+	return fc
+}
+
+var geoJSONTestCoords = []graph.Coordinate{
+	{Lat: 52.5, Lon: 13.4},
+	{Lat: 52.6, Lon: 13.5},
+	{Lat: 52.7, Lon: 13.6},
+}
+
+func TestGeoJSONOutputWritePath(t *testing.T) {
+	path := []graph.Edge{{From: 0, To: 1, Cost: 2}, {From: 1, To: 2, Cost: 3}}
+
+	var buf bytes.Buffer
+	if err := graphio.NewGeoJSONOutput(geoJSONTestCoords).WritePath(&buf, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fc := decodeFeatureCollection(t, buf.Bytes())
+	if len(fc.Features) != 4 {
+		t.Fatalf("expected a line and 3 points, got %d features", len(fc.Features))
+	}
+
+	line := fc.Features[0]
+	if line.Geometry.Type != "LineString" {
+		t.Errorf("expected a LineString, got %q", line.Geometry.Type)
+	}
+	if got := string(line.Geometry.Coordinates); got != "[[13.4,52.5],[13.5,52.6],[13.6,52.7]]" {
+		t.Errorf("unexpected coordinates %s", got)
+	}
+	if line.Properties["cost"] != 5.0 || line.Properties["hops"] != 2.0 {
+		t.Errorf("unexpected properties %v", line.Properties)
+	}
+
+	last := fc.Features[3]
+	if last.Properties["node"] != 3.0 || last.Properties["cost"] != 5.0 {
+		t.Errorf("unexpected properties of the target %v", last.Properties)
+	}
+}
+
+func TestGeoJSONOutputWriteGraph(t *testing.T) {
+	g := graph.NewAdjacencyList([]graph.Edge{{From: 0, To: 1, Cost: 1}, {From: 2, To: 0, Cost: 4}}, 3)
+
+	var buf bytes.Buffer
+	if err := graphio.NewGeoJSONOutput(geoJSONTestCoords).WriteGraph(&buf, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fc := decodeFeatureCollection(t, buf.Bytes())
+	if len(fc.Features) != 2 {
+		t.Fatalf("expected 2 edges, got %d features", len(fc.Features))
+	}
+
+	if p := fc.Features[1].Properties; p["from"] != 3.0 || p["to"] != 1.0 || p["cost"] != 4.0 {
+		t.Errorf("unexpected properties %v", p)
+	}
+
+	if err := graphio.NewGeoJSONOutput(geoJSONTestCoords[:2]).WriteGraph(&buf, g); err == nil {
+		t.Error("expected an error for missing coordinates")
+	}
+}
@@ -0,0 +1,228 @@
+package graphio
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"route-planning/graph"
+	"strconv"
+	"strings"
+)
+
+// pathPoint is a node of a path together with the distance in meters and the
+// cost along the path to reach it.
+type pathPoint struct {
+	node     graph.Node
+	coord    graph.Coordinate
+	distance float64
+	cost     float64
+}
+
+// pathPoints returns the points along path placed at coords.
+func pathPoints(coords []graph.Coordinate, path []graph.Edge) ([]pathPoint, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	for _, e := range path {
+		if int(e.From) >= len(coords) || int(e.To) >= len(coords) {
+			return nil, fmt.Errorf("no coordinates for edge %d -> %d", e.From+1, e.To+1)
+		}
+	}
+
+	points := make([]pathPoint, 0, len(path)+1)
+	points = append(points, pathPoint{node: path[0].From, coord: coords[path[0].From]})
+
+	for _, e := range path {
+		last := points[len(points)-1]
+		c := coords[e.To]
+		points = append(points, pathPoint{
+			node:     e.To,
+			coord:    c,
+			distance: last.distance + last.coord.Distance(c),
+			cost:     last.cost + e.Cost,
+		})
+	}
+
+	return points, nil
+}
+
+// GPXType selects how paths are stored in GPX files.
+type GPXType int
+
+const (
+	// GPXTrack stores paths as a track, i.e. a recorded trace.
+	GPXTrack GPXType = iota
+	// GPXRoute stores paths as a route, i.e. a list of route points to
+	// navigate along.
+	GPXRoute
+)
+
+type gpxPoint struct {
+	Lat         float64 `xml:"lat,attr"`
+	Lon         float64 `xml:"lon,attr"`
+	Name        string  `xml:"name"`
+	Description string  `xml:"desc"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+type gpxTrack struct {
+	Name     string     `xml:"name"`
+	Segments gpxSegment `xml:"trkseg"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+type gpxFile struct {
+	XMLName xml.Name  `xml:"http://www.topografix.com/GPX/1/1 gpx"`
+	Version string    `xml:"version,attr"`
+	Creator string    `xml:"creator,attr"`
+	Track   *gpxTrack `xml:"trk,omitempty"`
+	Route   *gpxRoute `xml:"rte,omitempty"`
+}
+
+type gpxOutput struct {
+	coords []graph.Coordinate
+	kind   GPXType
+}
+
+// NewGPXOutput returns a writer storing paths as GPX 1.1 tracks or routes
+// with the nodes placed at coords. Every point is named by its 1-based node
+// ID and describes the distance travelled so far.
+func NewGPXOutput(coords []graph.Coordinate, kind GPXType) PathWriter {
+	return gpxOutput{coords: coords, kind: kind}
+}
+
+func (gpx gpxOutput) WritePath(w io.Writer, path []graph.Edge) error {
+	points, err := pathPoints(gpx.coords, path)
+	if err != nil {
+		return err
+	}
+
+	gpxPoints := make([]gpxPoint, len(points))
+	for i, p := range points {
+		gpxPoints[i] = gpxPoint{
+			Lat:         p.coord.Lat,
+			Lon:         p.coord.Lon,
+			Name:        fmt.Sprint(p.node + 1),
+			Description: fmt.Sprintf("distance: %.1f m, cost: %g", p.distance, p.cost),
+		}
+	}
+
+	name := pathName(points)
+	file := gpxFile{Version: "1.1", Creator: "route-planning"}
+	if gpx.kind == GPXRoute {
+		file.Route = &gpxRoute{Name: name, Points: gpxPoints}
+	} else {
+		file.Track = &gpxTrack{Name: name, Segments: gpxSegment{Points: gpxPoints}}
+	}
+
+	return writeXML(w, file)
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlPlacemark struct {
+	Name       string    `xml:"name"`
+	Data       []kmlData `xml:"ExtendedData>Data"`
+	Point      *string   `xml:"Point>coordinates,omitempty"`
+	LineString *string   `xml:"LineString>coordinates,omitempty"`
+}
+
+type kmlFile struct {
+	XMLName    xml.Name       `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Name       string         `xml:"Document>name"`
+	Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+}
+
+type kmlOutput struct {
+	coords []graph.Coordinate
+}
+
+// NewKMLOutput returns a writer storing paths as KML documents with the nodes
+// placed at coords. The path is written as a line followed by a placemark for
+// every node carrying the distance and cost to reach it.
+func NewKMLOutput(coords []graph.Coordinate) PathWriter {
+	return kmlOutput{coords: coords}
+}
+
+func (kml kmlOutput) WritePath(w io.Writer, path []graph.Edge) error {
+	points, err := pathPoints(kml.coords, path)
+	if err != nil {
+		return err
+	}
+
+	file := kmlFile{Name: pathName(points)}
+
+	if len(points) > 0 {
+		var sb strings.Builder
+		for i, p := range points {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(kmlCoordinate(p.coord))
+		}
+		line := sb.String()
+
+		last := points[len(points)-1]
+		file.Placemarks = append(file.Placemarks, kmlPlacemark{
+			Name:       file.Name,
+			Data:       kmlPointData(last),
+			LineString: &line,
+		})
+	}
+
+	for _, p := range points {
+		coord := kmlCoordinate(p.coord)
+		file.Placemarks = append(file.Placemarks, kmlPlacemark{
+			Name:  fmt.Sprint(p.node + 1),
+			Data:  kmlPointData(p),
+			Point: &coord,
+		})
+	}
+
+	return writeXML(w, file)
+}
+
+// kmlCoordinate formats c without exponent, which KML does not allow.
+func kmlCoordinate(c graph.Coordinate) string {
+	return strconv.FormatFloat(c.Lon, 'f', -1, 64) + "," + strconv.FormatFloat(c.Lat, 'f', -1, 64)
+}
+
+func kmlPointData(p pathPoint) []kmlData {
+	return []kmlData{
+		{Name: "distance", Value: fmt.Sprintf("%.1f", p.distance)},
+		{Name: "cost", Value: fmt.Sprintf("%g", p.cost)},
+	}
+}
+
+func pathName(points []pathPoint) string {
+	if len(points) == 0 {
+		return "empty path"
+	}
+	return fmt.Sprintf("path from %d to %d", points[0].node+1, points[len(points)-1].node+1)
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
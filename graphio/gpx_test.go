@@ -0,0 +1,113 @@
+package graphio_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"strings"
+	"testing"
+)
+
+var gpxTestPath = []graph.Edge{{From: 0, To: 1, Cost: 2}, {From: 1, To: 2, Cost: 3}}
+
+func TestGPXOutput(t *testing.T) {
+	var buf bytes.Buffer
+	if err := graphio.NewGPXOutput(geoJSONTestCoords, graphio.GPXTrack).WritePath(&buf, gpxTestPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gpx struct {
+		Points []struct {
+			Lat  float64 `xml:"lat,attr"`
+			Lon  float64 `xml:"lon,attr"`
+			Name string  `xml:"name"`
+			Desc string  `xml:"desc"`
+		} `xml:"trk>trkseg>trkpt"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &gpx); err != nil {
+		t.Fatalf("invalid GPX: %v\n%s", err, buf.String())
+	}
+
+	if len(gpx.Points) != 3 {
+		t.Fatalf("expected 3 track points, got %d", len(gpx.Points))
+	}
+
+	last := gpx.Points[2]
+	if last.Lat != 52.7 || last.Lon != 13.6 || last.Name != "3" {
+		t.Errorf("unexpected last point %+v", last)
+	}
+
+	distance := geoJSONTestCoords[0].Distance(geoJSONTestCoords[1]) + geoJSONTestCoords[1].Distance(geoJSONTestCoords[2])
+	if !strings.Contains(last.Desc, "distance: "+formatMeters(distance)) {
+		t.Errorf("expected cumulative distance %.1f in %q", distance, last.Desc)
+	}
+
+	buf.Reset()
+	if err := graphio.NewGPXOutput(geoJSONTestCoords, graphio.GPXRoute).WritePath(&buf, gpxTestPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := strings.Count(buf.String(), "<rtept "); n != 3 {
+		t.Errorf("expected 3 route points, got %d", n)
+	}
+}
+
+func TestKMLOutput(t *testing.T) {
+	var buf bytes.Buffer
+	if err := graphio.NewKMLOutput(geoJSONTestCoords).WritePath(&buf, gpxTestPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var kml struct {
+		Placemarks []struct {
+			Name string `xml:"name"`
+			Data []struct {
+				Name  string `xml:"name,attr"`
+				Value string `xml:"value"`
+			} `xml:"ExtendedData>Data"`
+			Line string `xml:"LineString>coordinates"`
+		} `xml:"Document>Placemark"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &kml); err != nil {
+		t.Fatalf("invalid KML: %v\n%s", err, buf.String())
+	}
+
+	if len(kml.Placemarks) != 4 {
+		t.Fatalf("expected a line and 3 points, got %d placemarks", len(kml.Placemarks))
+	}
+
+	if got := kml.Placemarks[0].Line; got != "13.4,52.5 13.5,52.6 13.6,52.7" {
+		t.Errorf("unexpected line %q", got)
+	}
+
+	second := kml.Placemarks[2]
+	distance := geoJSONTestCoords[0].Distance(geoJSONTestCoords[1])
+	if second.Name != "2" || second.Data[0].Value != formatMeters(distance) || second.Data[1].Value != "2" {
+		t.Errorf("unexpected point %+v", second)
+	}
+}
+
+func TestKMLOutputSmallCoordinates(t *testing.T) {
+	coords := []graph.Coordinate{{Lat: 0.00001, Lon: -0.000002}, {Lat: 1, Lon: 2}}
+
+	var buf bytes.Buffer
+	if err := graphio.NewKMLOutput(coords).WritePath(&buf, gpxTestPath[:1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "-0.000002,0.00001 2,1") {
+		t.Errorf("expected coordinates without exponent, got\n%s", buf.String())
+	}
+}
+
+func TestGPXOutputMissingCoordinates(t *testing.T) {
+	var buf bytes.Buffer
+	if err := graphio.NewKMLOutput(geoJSONTestCoords[:2]).WritePath(&buf, gpxTestPath); err == nil {
+		t.Error("expected an error for missing coordinates")
+	}
+}
+
+func formatMeters(m float64) string {
+	return fmt.Sprintf("%.1f", m)
+}
@@ -1,12 +1,55 @@
 package graphio
 
-import "route-planning/graph"
+import (
+	"errors"
+	"io"
+	"route-planning/graph"
+)
+
+// ErrDirected is returned by LoadUndirectedGraph if the input describes a
+// directed graph.
+var ErrDirected = errors.New("graph is directed")
+
+// maxPreallocated bounds the number of elements preallocated for the counts
+// declared in headers, which are not trusted before the data confirms them.
+const maxPreallocated = 1 << 20
+
+// preallocated returns the capacity to preallocate for count declared
+// elements.
+func preallocated(count int) int {
+	if count > maxPreallocated {
+		return maxPreallocated
+	}
+	return count
+}
 
 type GraphInput interface {
 	LoadGraph() ([]graph.Edge, int, error)
 }
 
+// UndirectedGraphInput is implemented by inputs which can describe undirected
+// graphs. LoadUndirectedGraph returns every undirected edge only once.
+type UndirectedGraphInput interface {
+	LoadUndirectedGraph() ([]graph.Edge, int, error)
+}
+
+// CoordinateInput provides the coordinates of the nodes of a graph indexed by
+// node.
+type CoordinateInput interface {
+	LoadCoordinates() ([]graph.Coordinate, error)
+}
+
 type GraphOutput interface {
 	PrintNode(graph.Node) string
 	PrintEdge(graph.Edge) string
 }
+
+// GraphWriter writes all nodes and edges of a graph.
+type GraphWriter interface {
+	WriteGraph(io.Writer, graph.Graph) error
+}
+
+// PathWriter writes a path as returned by shortestpath.Algorithm.
+type PathWriter interface {
+	WritePath(io.Writer, []graph.Edge) error
+}
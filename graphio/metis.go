@@ -0,0 +1,406 @@
+package graphio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"route-planning/graph"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// METISInput reads undirected graphs in the METIS format. Besides the graph it
+// provides the optional vertex weights.
+type METISInput interface {
+	GraphInput
+	UndirectedGraphInput
+	// LoadVertexWeights returns the weights of every node, one per
+	// constraint, or nil if the graph has no vertex weights.
+	LoadVertexWeights() ([][]int64, error)
+}
+
+type metisInput struct {
+	src *source
+
+	// weights holds the vertex weights read with the graph so that they
+	// remain available for inputs which can only be read once.
+	weights       [][]int64
+	weightsLoaded bool
+}
+
+// NewMETISInput reads a graph in the METIS format as used by METIS and other
+// graph partitioners: a header 'n m [fmt [ncon]]' followed by one line per
+// node listing its optional vertex size and weights and its neighbours with
+// optional edge weights.
+func NewMETISInput(file string) METISInput {
+	return &metisInput{src: fileSource(file)}
+}
+
+// NewMETISInputFromReader reads a METIS graph from r. The graph can only be
+// loaded once.
+func NewMETISInputFromReader(r io.Reader) METISInput {
+	return &metisInput{src: readerSource(r)}
+}
+
+func (mi *metisInput) LoadGraph() ([]graph.Edge, int, error) {
+	g, err := mi.load()
+	if err != nil {
+		return nil, 0, err
+	}
+	return g.edges, g.n, nil
+}
+
+func (mi *metisInput) LoadUndirectedGraph() ([]graph.Edge, int, error) {
+	g, err := mi.load()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	undirected := g.edges[:0:0]
+	for _, e := range g.edges {
+		if e.From < e.To {
+			undirected = append(undirected, e)
+		}
+	}
+
+	return undirected, g.n, nil
+}
+
+// load parses the graph and checks the number of edges declared in the
+// header. Every undirected edge is listed by both of its nodes.
+func (mi *metisInput) load() (*metisGraph, error) {
+	g, err := mi.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(g.edges) != 2*g.m {
+		return nil, fmt.Errorf("expected METIS file to contain %d edges but got %d neighbour entries", g.m, len(g.edges))
+	}
+
+	if err := checkSymmetric(g); err != nil {
+		return nil, fmt.Errorf("METIS parsing failed: \n\t%w", inFile(err, mi.src))
+	}
+
+	return g, nil
+}
+
+// checkSymmetric checks that every neighbour of a node lists the node as
+// neighbour with the same weight.
+func checkSymmetric(g *metisGraph) error {
+	counts := make(map[graph.Edge]int, len(g.edges))
+	for _, e := range g.edges {
+		counts[e]++
+	}
+
+	for _, e := range g.edges {
+		if counts[e] != counts[e.Reverted()] {
+			return parseErrorf(g.lines[e.From], 0, "node %d lists neighbour %d with weight %g, but node %d does not list node %d with the same weight", e.From+1, e.To+1, e.Cost, e.To+1, e.From+1)
+		}
+	}
+
+	return nil
+}
+
+func (mi *metisInput) loadForValidation() (*validationGraph, error) {
+	g, err := mi.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]int, len(g.edges))
+	for i, e := range g.edges {
+		lines[i] = g.lines[e.From]
+	}
+
+	return &validationGraph{edges: g.edges, lines: lines, file: mi.src.String(), n: g.n, m: 2 * g.m}, nil
+}
+
+func (mi *metisInput) LoadVertexWeights() ([][]int64, error) {
+	if !mi.weightsLoaded {
+		if _, err := mi.parse(); err != nil {
+			return nil, err
+		}
+	}
+	return mi.weights, nil
+}
+
+// metisFormat is the fmt field of the header.
+type metisFormat struct {
+	sizes, weights, edgeWeights bool
+	// constraints is the number of weights per node.
+	constraints int
+}
+
+type metisGraph struct {
+	edges []graph.Edge
+	// lines is the line of every node.
+	lines []int
+	n, m  int
+}
+
+func (mi *metisInput) parse() (*metisGraph, error) {
+	f, err := mi.src.open()
+	if err != nil {
+		return nil, fmt.Errorf("error opening graph file %s: %w", mi.src, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// Lines of high degree nodes can exceed the default token size.
+	scanner.Buffer(nil, 64*1024*1024)
+
+	g, weights, err := parseMETIS(scanner)
+	if err := inFile(err, mi.src); err != nil {
+		return nil, fmt.Errorf("METIS parsing failed: \n\t%w", err)
+	}
+
+	mi.weights, mi.weightsLoaded = weights, true
+	return g, nil
+}
+
+// parseMETIS parses a METIS graph. Skipping malformed lines is not supported
+// as every line describes the node with its line number.
+func parseMETIS(s *bufio.Scanner) (*metisGraph, [][]int64, error) {
+	scanner := &lineScanner{Scanner: s}
+
+	line, ok := nextMETISLine(scanner)
+	if !ok {
+		return nil, nil, errors.New("missing header line")
+	}
+
+	n, m, format, err := parseMETISHeader(line)
+	if err != nil {
+		return nil, nil, &ParseError{Line: scanner.line, Err: err}
+	}
+
+	g := &metisGraph{
+		n:     n,
+		m:     m,
+		edges: make([]graph.Edge, 0, 2*preallocated(m)),
+		lines: make([]int, 0, preallocated(n)),
+	}
+
+	var weights [][]int64
+	if format.weights {
+		weights = make([][]int64, 0, preallocated(n))
+	}
+
+	for v := 0; v < n; v++ {
+		line, ok := nextMETISLine(scanner)
+		if !ok {
+			return nil, nil, fmt.Errorf("expected %d node lines but only got %d", n, v)
+		}
+		g.lines = append(g.lines, scanner.line)
+
+		fields := splitFields(line, unicode.IsSpace)
+		values := make([]int64, len(fields))
+		for i, field := range fields {
+			values[i], err = strconv.ParseInt(field.text, 10, 64)
+			if err != nil {
+				return nil, nil, parseErrorf(scanner.line, field.column, "error parsing line of node %d: %w", v+1, err)
+			}
+		}
+
+		if format.sizes {
+			if len(values) == 0 {
+				return nil, nil, parseErrorf(scanner.line, 0, "missing size of node %d", v+1)
+			}
+			values, fields = values[1:], fields[1:]
+		}
+
+		if format.weights {
+			if len(values) < format.constraints {
+				return nil, nil, parseErrorf(scanner.line, 0, "expected %d weights for node %d but got %d", format.constraints, v+1, len(values))
+			}
+			// Copy the weights to not keep the values of the whole line alive.
+			nodeWeights := make([]int64, format.constraints)
+			copy(nodeWeights, values)
+			weights = append(weights, nodeWeights)
+			values, fields = values[format.constraints:], fields[format.constraints:]
+		}
+
+		step := 1
+		if format.edgeWeights {
+			step = 2
+		}
+		if len(values)%step != 0 {
+			return nil, nil, parseErrorf(scanner.line, 0, "missing weight of the last edge of node %d", v+1)
+		}
+
+		for i := 0; i < len(values); i += step {
+			w := values[i]
+			if w < 1 || w > int64(n) {
+				return nil, nil, parseErrorf(scanner.line, fields[i].column, "neighbour %d of node %d out of range [1, %d]", w, v+1, n)
+			}
+
+			cost := 1.0
+			if format.edgeWeights {
+				cost = float64(values[i+1])
+			}
+
+			g.edges = append(g.edges, graph.Edge{
+				From: graph.Node(v),
+				To:   graph.Node(w - 1),
+				Cost: cost,
+			})
+		}
+	}
+
+	if _, ok := nextMETISLine(scanner); ok {
+		return nil, nil, parseErrorf(scanner.line, 0, "expected %d node lines but got more", n)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return g, weights, nil
+}
+
+func parseMETISHeader(line string) (int, int, metisFormat, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || len(fields) > 4 {
+		return 0, 0, metisFormat{}, fmt.Errorf("expected header line of format '{n} {m} [fmt [ncon]]' but got %q", line)
+	}
+
+	n, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, metisFormat{}, fmt.Errorf("error parsing {n} in header line: %w", err)
+	}
+	if n < 0 {
+		return 0, 0, metisFormat{}, fmt.Errorf("negative {n} %d in header line", n)
+	}
+
+	m, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, metisFormat{}, fmt.Errorf("error parsing {m} in header line: %w", err)
+	}
+	if m < 0 {
+		return 0, 0, metisFormat{}, fmt.Errorf("negative {m} %d in header line", m)
+	}
+
+	format := metisFormat{constraints: 1}
+
+	if len(fields) > 2 {
+		code := fields[2]
+		if len(code) > 3 || strings.Trim(code, "01") != "" {
+			return 0, 0, metisFormat{}, fmt.Errorf("invalid fmt %q in header line", code)
+		}
+		code = strings.Repeat("0", 3-len(code)) + code
+
+		format.sizes = code[0] == '1'
+		format.weights = code[1] == '1'
+		format.edgeWeights = code[2] == '1'
+	}
+
+	if len(fields) > 3 {
+		format.constraints, err = strconv.Atoi(fields[3])
+		if err != nil || format.constraints < 1 {
+			return 0, 0, metisFormat{}, fmt.Errorf("invalid ncon %q in header line", fields[3])
+		}
+	}
+
+	return n, m, format, nil
+}
+
+// nextMETISLine returns the next line skipping comments. Empty lines are
+// returned because they describe nodes without neighbours.
+func nextMETISLine(scanner *lineScanner) (string, bool) {
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "%") {
+			return line, true
+		}
+	}
+	return "", false
+}
+
+type metisOutput struct {
+	weights [][]int64
+}
+
+// NewMETISOutput returns a writer for METIS files. The graph must be
+// undirected, i.e. contain the reverse of every edge with the same cost, and
+// have positive integer costs. weights are the vertex weights of every node
+// and may be nil.
+func NewMETISOutput(weights [][]int64) GraphWriter {
+	return metisOutput{weights: weights}
+}
+
+func (mo metisOutput) WriteGraph(w io.Writer, g graph.Graph) error {
+	var edges []graph.Edge
+	unitCosts := true
+	for v := 0; v < g.N(); v++ {
+		for _, e := range g.OutgoingEdges(graph.Node(v)) {
+			if e.Cost < 1 || e.Cost != math.Trunc(e.Cost) || math.IsInf(e.Cost, 0) {
+				return fmt.Errorf("METIS requires positive integer costs but edge %d -> %d has cost %g", e.From+1, e.To+1, e.Cost)
+			}
+			unitCosts = unitCosts && e.Cost == 1
+			edges = append(edges, e)
+		}
+	}
+
+	if !isSymmetric(edges) {
+		return errors.New("METIS requires an undirected graph without self-loops")
+	}
+
+	constraints := 0
+	if mo.weights != nil {
+		if len(mo.weights) != g.N() {
+			return fmt.Errorf("got vertex weights for %d nodes but the graph has %d", len(mo.weights), g.N())
+		}
+		if g.N() > 0 {
+			constraints = len(mo.weights[0])
+		}
+		for v, weights := range mo.weights {
+			if len(weights) != constraints || constraints == 0 {
+				return fmt.Errorf("expected %d vertex weights for node %d but got %d", constraints, v+1, len(weights))
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "%d %d", g.N(), len(edges)/2)
+	switch {
+	case constraints > 1:
+		fmt.Fprintf(bw, " %d%d %d", 1, boolDigit(!unitCosts), constraints)
+	case constraints == 1:
+		fmt.Fprintf(bw, " %d%d", 1, boolDigit(!unitCosts))
+	case !unitCosts:
+		fmt.Fprint(bw, " 1")
+	}
+	bw.WriteByte('\n')
+
+	for v := 0; v < g.N(); v++ {
+		var fields []string
+		if constraints > 0 {
+			for _, weight := range mo.weights[v] {
+				fields = append(fields, strconv.FormatInt(weight, 10))
+			}
+		}
+
+		for _, e := range g.OutgoingEdges(graph.Node(v)) {
+			fields = append(fields, strconv.Itoa(int(e.To)+1))
+			if !unitCosts {
+				fields = append(fields, strconv.FormatInt(int64(e.Cost), 10))
+			}
+		}
+
+		bw.WriteString(strings.Join(fields, " "))
+		bw.WriteByte('\n')
+	}
+
+	return bw.Flush()
+}
+
+func boolDigit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
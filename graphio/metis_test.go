@@ -0,0 +1,117 @@
+package graphio_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"strings"
+	"testing"
+)
+
+func TestMETISInput(t *testing.T) {
+	data := "% sizes, weights and edge weights with 2 constraints\n3 2 111 2\n9 1 2 2 4\n9 3 4 1 4 3 7\n9 5 6 2 7\n"
+	in := graphio.NewMETISInput(writeTestFile(t, "graph.metis", data))
+
+	edges, n, err := in.LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []graph.Edge{
+		{From: 0, To: 1, Cost: 4},
+		{From: 1, To: 0, Cost: 4},
+		{From: 1, To: 2, Cost: 7},
+		{From: 2, To: 1, Cost: 7},
+	}
+	if n != 3 || !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected 3 nodes and %v, got %d nodes and %v", expected, n, edges)
+	}
+
+	edges, _, err = in.LoadUndirectedGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []graph.Edge{expected[0], expected[2]}; !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected undirected edges %v, got %v", expected, edges)
+	}
+
+	weights, err := in.LoadVertexWeights()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := [][]int64{{1, 2}, {3, 4}, {5, 6}}; !reflect.DeepEqual(weights, expected) {
+		t.Errorf("expected vertex weights %v, got %v", expected, weights)
+	}
+}
+
+func TestMETISInputInvalid(t *testing.T) {
+	tests := map[string]string{
+		"edge count":          "2 2\n2\n1\n",
+		"neighbour range":     "2 1\n3\n1\n",
+		"missing node lines":  "3 1\n2\n1\n",
+		"missing edge weight": "2 1 1\n2\n1 1\n",
+		"negative n":          "-2 1 010\n",
+		"negative m":          "2 -1\n2\n1\n",
+	}
+
+	for name, data := range tests {
+		if _, _, err := graphio.NewMETISInputFromReader(strings.NewReader(data)).LoadGraph(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMETISInputAsymmetric(t *testing.T) {
+	tests := map[string]string{
+		"missing neighbour": "3 2\n2\n1 3\n1\n",
+		"different weights": "3 2 001\n2 1\n1 1 3 4\n2 5\n",
+	}
+
+	for name, data := range tests {
+		_, _, err := graphio.NewMETISInputFromReader(strings.NewReader(data)).LoadUndirectedGraph()
+
+		var pe *graphio.ParseError
+		if !errors.As(err, &pe) || pe.Line != 3 {
+			t.Errorf("%s: expected a ParseError in line 3, got %v", name, err)
+		}
+	}
+}
+
+func TestMETISOutput(t *testing.T) {
+	g := newUndirected(t, []graph.Edge{{From: 0, To: 1, Cost: 4}, {From: 1, To: 2, Cost: 7}}, 4)
+	weights := [][]int64{{1}, {2}, {3}, {4}}
+
+	var buf bytes.Buffer
+	if err := graphio.NewMETISOutput(weights).WriteGraph(&buf, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := "4 2 11\n1 2 4\n2 1 4 3 7\n3 2 7\n4\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	in := graphio.NewMETISInputFromReader(&buf)
+	edges, n, err := in.LoadUndirectedGraph()
+	if err != nil {
+		t.Fatalf("unexpected error reading the written graph: %v", err)
+	}
+	if expected := []graph.Edge{{From: 0, To: 1, Cost: 4}, {From: 1, To: 2, Cost: 7}}; n != 4 || !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected 4 nodes and %v, got %d nodes and %v", expected, n, edges)
+	}
+
+	// The vertex weights are kept although the reader was consumed.
+	if got, err := in.LoadVertexWeights(); err != nil || !reflect.DeepEqual(got, weights) {
+		t.Errorf("expected vertex weights %v, got %v (%v)", weights, got, err)
+	}
+}
+
+func TestMETISOutputRequiresUndirectedGraph(t *testing.T) {
+	g := graph.NewAdjacencyList([]graph.Edge{{From: 0, To: 1, Cost: 1}}, 2)
+
+	var buf bytes.Buffer
+	if err := graphio.NewMETISOutput(nil).WriteGraph(&buf, g); err == nil {
+		t.Error("expected an error for a directed graph")
+	}
+}
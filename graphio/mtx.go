@@ -4,141 +4,510 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
-	"os"
+	"io"
+	"math"
 	"route-planning/graph"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 type mtxInput struct {
-	file string
+	src     *source
+	opts    parseOptions
+	skipped []*ParseError
+
+	// directed holds the graph of a general matrix read from a reader by
+	// LoadUndirectedGraph, so that LoadGraph can still return it.
+	directed *mtxGraph
 }
 
-func NewMTXInput(file string) GraphInput {
-	return &mtxInput{file: file}
+type mtxGraph struct {
+	edges []graph.Edge
+	n     int
 }
 
-func (mi mtxInput) LoadGraph() ([]graph.Edge, int, error) {
-	f, err := os.Open(mi.file)
-	if err != nil {
-		return nil, 0, fmt.Errorf("error opening graph file: %w", err)
+// NewMTXInput reads a MatrixMarket .mtx file. Malformed data lines can be
+// skipped, see WithLenient.
+func NewMTXInput(file string, opts ...ParseOption) GraphInput {
+	return &mtxInput{src: fileSource(file), opts: newParseOptions(opts)}
+}
+
+// NewMTXInputFromReader reads a MatrixMarket graph from r. The graph can only
+// be loaded once, except for a general matrix first requested by
+// LoadUndirectedGraph.
+func NewMTXInputFromReader(r io.Reader, opts ...ParseOption) GraphInput {
+	return &mtxInput{src: readerSource(r), opts: newParseOptions(opts)}
+}
+
+func (mi *mtxInput) LoadGraph() ([]graph.Edge, int, error) {
+	if g := mi.directed; g != nil {
+		mi.directed = nil
+		return g.edges, g.n, nil
 	}
-	defer f.Close()
+	return mi.load(false)
+}
+
+// LoadUndirectedGraph returns every entry of a symmetric matrix once and
+// ErrDirected for general matrices.
+func (mi *mtxInput) LoadUndirectedGraph() ([]graph.Edge, int, error) {
+	return mi.load(true)
+}
 
-	scanner := bufio.NewScanner(f)
+func (mi *mtxInput) loadForValidation() (*validationGraph, error) {
+	g := &validationGraph{file: mi.src.String()}
 
-	if err := parseHeader(scanner); err != nil {
-		return nil, 0, err
+	n, m, header, err := mi.stream(false, false,
+		func(n, m int, header mtxHeader) {
+			g.edges = make([]graph.Edge, 0, header.edgesPerEntry(false)*preallocated(m))
+			g.lines = make([]int, 0, header.edgesPerEntry(false)*preallocated(m))
+		},
+		func(e graph.Edge, line int) error {
+			g.edges = append(g.edges, e)
+			g.lines = append(g.lines, line)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	n, m, err := parseSize(scanner)
+	g.n, g.m = n, header.edgesPerEntry(false)*m
+	return g, nil
+}
+
+// load parses the mtx file. Unless undirected is set, every entry of a
+// symmetric matrix results in an edge for each direction.
+func (mi *mtxInput) load(undirected bool) ([]graph.Edge, int, error) {
+	edges, n, m, header, err := mi.parse(undirected)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	edges, err := parseData(scanner, m)
-	if err != nil {
+	if err := mi.checkEntries(len(edges), m, header.edgesPerEntry(undirected)); err != nil {
 		return nil, 0, err
 	}
 
+	if undirected && !header.symmetric {
+		// Only reached for readers, which cannot be parsed again.
+		mi.directed = &mtxGraph{edges: edges, n: n}
+		return nil, 0, ErrDirected
+	}
+
 	return edges, n, nil
 }
 
-func parseHeader(scanner *bufio.Scanner) error {
+// parse returns the edges, the number of nodes, the number of entries
+// declared in the size line and the header.
+func (mi *mtxInput) parse(undirected bool) ([]graph.Edge, int, int, mtxHeader, error) {
+	var edges []graph.Edge
+
+	n, m, header, err := mi.stream(undirected, false,
+		func(n, m int, header mtxHeader) {
+			edges = make([]graph.Edge, 0, header.edgesPerEntry(undirected)*preallocated(m))
+		},
+		func(e graph.Edge, line int) error {
+			edges = append(edges, e)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, 0, 0, header, err
+	}
+
+	return edges, n, m, header, nil
+}
+
+// StreamEdges calls visit for every edge returned by LoadGraph without
+// keeping them in memory.
+func (mi *mtxInput) StreamEdges(nodes func(n int), visit func(graph.Edge) error) (int, error) {
+	return mi.streamEdgeLines(nodes, func(e graph.Edge, line int) error { return visit(e) })
+}
+
+func (mi *mtxInput) streamEdgeLines(nodes func(n int), visit func(e graph.Edge, line int) error) (int, error) {
+	count := 0
+	n, m, header, err := mi.stream(false, true, func(n, m int, h mtxHeader) { nodes(n) }, func(e graph.Edge, line int) error {
+		count++
+		return visit(e, line)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := mi.checkEntries(count, m, header.edgesPerEntry(false)); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// checkEntries checks that the data lines read, including skipped ones, match
+// the m entries declared in the size line.
+func (mi *mtxInput) checkEntries(numEdges, m, edgesPerLine int) error {
+	if lines := numEdges/edgesPerLine + len(mi.skipped); lines != m {
+		return fmt.Errorf("expected mtx file to contain %d data lines but only got %d", m, lines)
+	}
+	return nil
+}
+
+func (mi *mtxInput) file() string {
+	return mi.src.String()
+}
+
+func (mi *mtxInput) rereadable() bool {
+	return mi.src.rereadable()
+}
+
+func (mi *mtxInput) SkippedLines() []*ParseError {
+	return mi.skipped
+}
+
+// stream calls header with the declared size and visit for every edge and its
+// line. It
+// returns the number of nodes, the number of entries declared in the size
+// line and the header.
+func (mi *mtxInput) stream(undirected, checkNodes bool, header func(n, m int, h mtxHeader), visit func(e graph.Edge, line int) error) (int, int, mtxHeader, error) {
+	errs := &lineErrors{src: mi.src, lenient: mi.opts.lenient}
+	n, m, h, err := mi.read(errs, undirected, checkNodes, header, visit)
+	mi.skipped = errs.skipped
+	// Errors of the header and size line are returned unwrapped.
+	return n, m, h, inFile(err, mi.src)
+}
+
+func (mi *mtxInput) read(errs *lineErrors, undirected, checkNodes bool, header func(n, m int, h mtxHeader), visit func(e graph.Edge, line int) error) (int, int, mtxHeader, error) {
+	f, err := mi.src.open()
+	if err != nil {
+		return 0, 0, mtxHeader{}, fmt.Errorf("error opening graph file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := &lineScanner{Scanner: bufio.NewScanner(f)}
+
+	h, err := parseHeader(scanner)
+	if err != nil {
+		return 0, 0, h, err
+	}
+
+	if undirected && !h.symmetric && mi.src.rereadable() {
+		return 0, 0, h, ErrDirected
+	}
+
+	n, m, err := parseSize(scanner)
+	if err != nil {
+		return 0, 0, h, err
+	}
+
+	header(n, m, h)
+
+	nodes := uncheckedNodes
+	if checkNodes {
+		nodes = n
+	}
+
+	if err := parseData(scanner, errs, h, nodes, h.edgesPerEntry(undirected) == 2, visit); err != nil {
+		return 0, 0, h, err
+	}
+
+	return n, m, h, nil
+}
+
+// mtxHeader describes the matrix stored in an mtx file.
+type mtxHeader struct {
+	// field is the type of the entries: pattern, real or integer. Entries of
+	// pattern matrices have no value and result in edges of cost 1.
+	field string
+	// symmetric is set if only the lower triangle of a symmetric matrix is
+	// stored, otherwise every entry describes a directed edge.
+	symmetric bool
+}
+
+// edgesPerEntry returns the number of edges created for every entry.
+func (h mtxHeader) edgesPerEntry(undirected bool) int {
+	if h.symmetric && !undirected {
+		return 2
+	}
+	return 1
+}
+
+func parseHeader(scanner *lineScanner) (mtxHeader, error) {
 	if !scanner.Scan() {
-		return errors.New("empty file")
+		if err := scanner.Err(); err != nil {
+			return mtxHeader{}, err
+		}
+		return mtxHeader{}, errors.New("empty file")
 	}
 
 	headerLine := scanner.Text()
 
-	if headerLine[0] != '%' {
-		return fmt.Errorf("header line expected to begin with '%%', but got '%s'", string(headerLine[0]))
+	if headerLine == "" || headerLine[0] != '%' {
+		return mtxHeader{}, parseErrorf(scanner.line, 0, "header line expected to begin with '%%', but got %q", headerLine)
 	}
 
-	toParse := headerLine[1:]
-	if toParse[0] == '%' {
-		toParse = toParse[1:]
+	toParse := strings.TrimLeft(headerLine, "%")
+
+	// The banner is case-insensitive.
+	fields := splitFields(strings.ToLower(headerLine), func(r rune) bool {
+		return r == '%' || unicode.IsSpace(r)
+	})
+	if len(fields) != 5 || fields[0].text != "matrixmarket" || fields[1].text != "matrix" {
+		return mtxHeader{}, parseErrorf(scanner.line, 0, "unsupported mtx format: %s", toParse)
 	}
 
-	if toParse != "MatrixMarket matrix coordinate pattern symmetric" {
-		return fmt.Errorf("unsupported mtx format: %s", toParse)
+	if fields[2].text != "coordinate" {
+		return mtxHeader{}, parseErrorf(scanner.line, fields[2].column, "unsupported mtx format %q: only sparse coordinate matrices describe graphs", fields[2].text)
 	}
 
-	return nil
+	header := mtxHeader{field: fields[3].text}
+
+	switch header.field {
+	case "pattern", "real", "integer":
+	case "complex":
+		return mtxHeader{}, parseErrorf(scanner.line, fields[3].column, "unsupported mtx field \"complex\": complex values cannot be used as edge costs")
+	default:
+		return mtxHeader{}, parseErrorf(scanner.line, fields[3].column, "unsupported mtx field %q", header.field)
+	}
+
+	switch fields[4].text {
+	case "symmetric":
+		header.symmetric = true
+	case "general":
+	case "skew-symmetric":
+		return mtxHeader{}, parseErrorf(scanner.line, fields[4].column, "unsupported mtx symmetry \"skew-symmetric\": the implied reverse edges would have negated costs")
+	default:
+		return mtxHeader{}, parseErrorf(scanner.line, fields[4].column, "unsupported mtx symmetry %q", fields[4].text)
+	}
+
+	return header, nil
 }
 
-func parseSize(scanner *bufio.Scanner) (int, int, error) {
+func parseSize(scanner *lineScanner) (int, int, error) {
 	sizeLine, err := skipComments(scanner)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	fields := strings.Split(sizeLine, " ")
+	fields := splitFields(sizeLine, unicode.IsSpace)
+	if len(fields) != 3 {
+		return 0, 0, parseErrorf(scanner.line, 0, "expected size line of format '{rows} {columns} {entries}' but got %q", sizeLine)
+	}
 
-	n, err := strconv.Atoi(fields[0])
+	n, err := strconv.Atoi(fields[0].text)
 	if err != nil {
-		return 0, 0, fmt.Errorf("error parsing size line '%s': %w", sizeLine, err)
+		return 0, 0, parseErrorf(scanner.line, fields[0].column, "error parsing size line '%s': %w", sizeLine, err)
+	}
+	if n < 0 {
+		return 0, 0, parseErrorf(scanner.line, fields[0].column, "error parsing size line '%s': negative number of rows", sizeLine)
 	}
 
-	m, err := strconv.Atoi(fields[1])
+	m, err := strconv.Atoi(fields[1].text)
 	if err != nil {
-		return 0, 0, fmt.Errorf("error parsing size line '%s': %w", sizeLine, err)
+		return 0, 0, parseErrorf(scanner.line, fields[1].column, "error parsing size line '%s': %w", sizeLine, err)
 	}
 
 	if m != n {
-		return 0, 0, fmt.Errorf("error parsing size line '%s': expected m to equal n", sizeLine)
+		return 0, 0, parseErrorf(scanner.line, fields[1].column, "error parsing size line '%s': expected m to equal n", sizeLine)
 	}
 
-	nonzeros, err := strconv.Atoi(fields[2])
+	nonzeros, err := strconv.Atoi(fields[2].text)
 	if err != nil {
-		return 0, 0, fmt.Errorf("error parsing size line '%s': %w", sizeLine, err)
+		return 0, 0, parseErrorf(scanner.line, fields[2].column, "error parsing size line '%s': %w", sizeLine, err)
+	}
+	if nonzeros < 0 {
+		return 0, 0, parseErrorf(scanner.line, fields[2].column, "error parsing size line '%s': negative number of entries", sizeLine)
 	}
 
 	return n, nonzeros, nil
 }
 
-func parseData(scanner *bufio.Scanner, m int) ([]graph.Edge, error) {
-	edges := make([]graph.Edge, 0, 2*m)
-
+func parseData(scanner *lineScanner, errs *lineErrors, header mtxHeader, n int, bothDirections bool, visit func(e graph.Edge, line int) error) error {
 	for scanner.Scan() {
-		nodes := strings.Split(scanner.Text(), " ")
-
-		v, err := strconv.Atoi(nodes[0])
+		e, ok, err := parseDataLine(scanner.Text(), scanner.line, header, n)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse '%s': %w", nodes[0], err)
+			if err := errs.skip(err); err != nil {
+				return err
+			}
+			continue
 		}
-		w, err := strconv.Atoi(nodes[1])
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse '%s': %w", nodes[1], err)
+		if !ok {
+			continue
 		}
 
-		edges = append(edges, graph.Edge{
-			From: graph.Node(v - 1),
-			To:   graph.Node(w - 1),
-			Cost: 1.0,
-		}, graph.Edge{
-			From: graph.Node(w - 1),
-			To:   graph.Node(v - 1),
-			Cost: 1.0,
-		})
+		if err := visit(e, scanner.line); err != nil {
+			return err
+		}
 
+		if bothDirections {
+			if err := visit(e.Reverted(), scanner.line); err != nil {
+				return err
+			}
+		}
 	}
 
-	if numEdges := len(edges); numEdges != 2*m {
-		return nil, fmt.Errorf("expected mtx file to contain %d data lines but only got %d", m, numEdges/2)
+	return scanner.Err()
+}
+
+// parseDataLine parses the data line with the given line number. ok is false
+// for empty lines and comments. Unless n is uncheckedNodes, the row and column
+// have to be in [1, n].
+func parseDataLine(text string, line int, header mtxHeader, n int) (e graph.Edge, ok bool, err error) {
+	fields := splitFields(text, unicode.IsSpace)
+	if len(fields) == 0 || fields[0].text[0] == '%' {
+		return graph.Edge{}, false, nil
 	}
 
-	return edges, nil
+	expectedFields := 3
+	if header.field == "pattern" {
+		expectedFields = 2
+	}
+
+	if len(fields) != expectedFields {
+		return graph.Edge{}, false, parseErrorf(line, 0, "expected %d values in data line but got %q", expectedFields, text)
+	}
+
+	v, err := strconv.Atoi(fields[0].text)
+	if err != nil {
+		return graph.Edge{}, false, parseErrorf(line, fields[0].column, "unable to parse '%s': %w", fields[0].text, err)
+	}
+	w, err := strconv.Atoi(fields[1].text)
+	if err != nil {
+		return graph.Edge{}, false, parseErrorf(line, fields[1].column, "unable to parse '%s': %w", fields[1].text, err)
+	}
+
+	if n != uncheckedNodes {
+		for i, node := range []int{v, w} {
+			if node < 1 || node > n {
+				return graph.Edge{}, false, parseErrorf(line, fields[i].column, "node %d out of range [1, %d]", node, n)
+			}
+		}
+	}
+
+	cost := 1.0
+	if header.field != "pattern" {
+		cost, err = parseEntry(fields[2].text, header.field)
+		if err != nil {
+			return graph.Edge{}, false, &ParseError{Line: line, Column: fields[2].column, Err: err}
+		}
+	}
+
+	return graph.Edge{
+		From: graph.Node(v - 1),
+		To:   graph.Node(w - 1),
+		Cost: cost,
+	}, true, nil
+}
+
+// parseEntry returns the value of a data line as edge cost.
+func parseEntry(value string, field string) (float64, error) {
+	switch field {
+	case "integer":
+		cost, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse integer value '%s': %w", value, err)
+		}
+		return float64(cost), nil
+	case "real":
+		cost, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse real value '%s': %w", value, err)
+		}
+		return cost, nil
+	default:
+		return 1.0, nil
+	}
 }
 
-func skipComments(scanner *bufio.Scanner) (string, error) {
-	var line string
+// skipComments returns the next line which is neither empty nor a comment.
+func skipComments(scanner *lineScanner) (string, error) {
 	for scanner.Scan() {
-		line = scanner.Text()
-		if line[0] != '%' {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && line[0] != '%' {
 			return line, nil
 		}
 	}
 
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
 	return "", errors.New("mtx only contains header and comments")
 }
+
+type mtxOutput struct{}
+
+// NewMTXOutput returns a writer for MatrixMarket files. Symmetric graphs are
+// written as symmetric matrices, graphs with only unit costs as pattern
+// matrices.
+func NewMTXOutput() GraphWriter {
+	return mtxOutput{}
+}
+
+func (mtxOutput) WriteGraph(w io.Writer, g graph.Graph) error {
+	var edges []graph.Edge
+	for v := 0; v < g.N(); v++ {
+		edges = append(edges, g.OutgoingEdges(graph.Node(v))...)
+	}
+
+	header := mtxHeader{field: "pattern", symmetric: isSymmetric(edges)}
+	for _, e := range edges {
+		if e.Cost != 1 {
+			header.field = "integer"
+		}
+		if e.Cost != math.Trunc(e.Cost) || math.IsInf(e.Cost, 0) {
+			header.field = "real"
+			break
+		}
+	}
+
+	if header.symmetric {
+		// Only keep the lower triangle.
+		lower := edges[:0]
+		for _, e := range edges {
+			if e.From > e.To {
+				lower = append(lower, e)
+			}
+		}
+		edges = lower
+	}
+
+	symmetry := "general"
+	if header.symmetric {
+		symmetry = "symmetric"
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%%%%MatrixMarket matrix coordinate %s %s\n", header.field, symmetry)
+	fmt.Fprintf(bw, "%d %d %d\n", g.N(), g.N(), len(edges))
+
+	for _, e := range edges {
+		switch header.field {
+		case "pattern":
+			fmt.Fprintf(bw, "%d %d\n", e.From+1, e.To+1)
+		case "integer":
+			fmt.Fprintf(bw, "%d %d %d\n", e.From+1, e.To+1, int64(e.Cost))
+		default:
+			fmt.Fprintf(bw, "%d %d %s\n", e.From+1, e.To+1, strconv.FormatFloat(e.Cost, 'g', -1, 64))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// isSymmetric returns whether every edge has a reverse edge with the same
+// cost and there are no self-loops, which cannot be represented in symmetric
+// matrices as read by mtxInput.
+func isSymmetric(edges []graph.Edge) bool {
+	counts := make(map[graph.Edge]int, len(edges))
+	for _, e := range edges {
+		if e.From == e.To {
+			return false
+		}
+		counts[e]++
+	}
+
+	for _, e := range edges {
+		if counts[e] != counts[e.Reverted()] {
+			return false
+		}
+	}
+
+	return true
+}
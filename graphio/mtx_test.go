@@ -0,0 +1,192 @@
+package graphio_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, name, data string) string {
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestMTXInputFormats(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		edges      []graph.Edge
+		undirected []graph.Edge
+	}{
+		{
+			name: "pattern symmetric",
+			data: "%%MatrixMarket matrix coordinate pattern symmetric\n3 3 2\n2 1\n3 2\n",
+			edges: []graph.Edge{
+				{From: 1, To: 0, Cost: 1}, {From: 0, To: 1, Cost: 1},
+				{From: 2, To: 1, Cost: 1}, {From: 1, To: 2, Cost: 1},
+			},
+			undirected: []graph.Edge{{From: 1, To: 0, Cost: 1}, {From: 2, To: 1, Cost: 1}},
+		},
+		{
+			name: "real symmetric",
+			data: "%%MatrixMarket matrix coordinate real symmetric\n% comment\n3 3 2\n2 1 0.5\n3  2  1e1\n",
+			edges: []graph.Edge{
+				{From: 1, To: 0, Cost: 0.5}, {From: 0, To: 1, Cost: 0.5},
+				{From: 2, To: 1, Cost: 10}, {From: 1, To: 2, Cost: 10},
+			},
+			undirected: []graph.Edge{{From: 1, To: 0, Cost: 0.5}, {From: 2, To: 1, Cost: 10}},
+		},
+		{
+			name:  "integer general",
+			data:  "%%MatrixMarket Matrix Coordinate Integer General\n3 3 2\n1 2 4\n2 1 7\n",
+			edges: []graph.Edge{{From: 0, To: 1, Cost: 4}, {From: 1, To: 0, Cost: 7}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := writeTestFile(t, "graph.mtx", test.data)
+			in := graphio.NewMTXInput(file)
+
+			edges, n, err := in.LoadGraph()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != 3 || !reflect.DeepEqual(edges, test.edges) {
+				t.Errorf("expected 3 nodes and %v, got %d nodes and %v", test.edges, n, edges)
+			}
+
+			edges, _, err = in.(graphio.UndirectedGraphInput).LoadUndirectedGraph()
+			if test.undirected == nil {
+				if !errors.Is(err, graphio.ErrDirected) {
+					t.Errorf("expected ErrDirected, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(edges, test.undirected) {
+				t.Errorf("expected undirected edges %v, got %v", test.undirected, edges)
+			}
+		})
+	}
+}
+
+func TestMTXInputUnsupported(t *testing.T) {
+	tests := map[string]string{
+		"skew-symmetric": "%%MatrixMarket matrix coordinate real skew-symmetric\n2 2 1\n2 1 1.0\n",
+		"complex":        "%%MatrixMarket matrix coordinate complex general\n2 2 1\n2 1 1.0 2.0\n",
+		"array":          "%%MatrixMarket matrix array real general\n2 2\n1.0\n",
+	}
+
+	for expected, data := range tests {
+		file := writeTestFile(t, "graph.mtx", data)
+
+		_, _, err := graphio.NewMTXInput(file).LoadGraph()
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error mentioning %q, got %v", expected, err)
+		}
+	}
+}
+
+func TestMTXInputFromReader(t *testing.T) {
+	data := "%%MatrixMarket matrix coordinate integer general\n2 2 1\n1 2 4\n"
+	in := graphio.NewMTXInputFromReader(strings.NewReader(data))
+
+	// A general matrix is kept after detecting that it is directed because
+	// the reader cannot be read again.
+	if _, _, err := in.(graphio.UndirectedGraphInput).LoadUndirectedGraph(); !errors.Is(err, graphio.ErrDirected) {
+		t.Fatalf("expected ErrDirected, got %v", err)
+	}
+
+	edges, n, err := in.LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []graph.Edge{{From: 0, To: 1, Cost: 4}}; n != 2 || !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected 2 nodes and %v, got %d nodes and %v", expected, n, edges)
+	}
+
+	if _, _, err := in.LoadGraph(); err == nil {
+		t.Error("expected an error reading the consumed reader again")
+	}
+}
+
+func TestMTXOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		g      graph.Graph
+		header string
+	}{
+		{
+			name:   "symmetric pattern",
+			g:      newUndirected(t, []graph.Edge{{From: 0, To: 1, Cost: 1}, {From: 2, To: 1, Cost: 1}}, 3),
+			header: "%%MatrixMarket matrix coordinate pattern symmetric\n3 3 2\n",
+		},
+		{
+			name:   "general real",
+			g:      graph.NewAdjacencyList([]graph.Edge{{From: 0, To: 1, Cost: 1.5}, {From: 1, To: 0, Cost: 2}}, 3),
+			header: "%%MatrixMarket matrix coordinate real general\n3 3 2\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := graphio.NewMTXOutput().WriteGraph(&buf, test.g); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.HasPrefix(buf.String(), test.header) {
+				t.Errorf("expected header %q, got %q", test.header, buf.String())
+			}
+
+			edges, n, err := graphio.NewMTXInputFromReader(&buf).LoadGraph()
+			if err != nil {
+				t.Fatalf("unexpected error reading the written graph: %v", err)
+			}
+
+			reread := newCSR(t, edges, n)
+			for v := 0; v < test.g.N(); v++ {
+				want := sortedEdges(test.g.OutgoingEdges(graph.Node(v)))
+				if got := sortedEdges(reread.OutgoingEdges(graph.Node(v))); !reflect.DeepEqual(got, want) {
+					t.Errorf("outgoing edges of %d: expected %v, got %v", v, want, got)
+				}
+			}
+		})
+	}
+}
+
+func newUndirected(t *testing.T, edges []graph.Edge, n int) graph.Graph {
+	g, err := graph.NewUndirected(edges, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func newCSR(t *testing.T, edges []graph.Edge, n int) *graph.CSR {
+	g, err := graph.NewCSR(edges, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func sortedEdges(edges []graph.Edge) []graph.Edge {
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
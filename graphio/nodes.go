@@ -0,0 +1,111 @@
+package graphio
+
+import (
+	"errors"
+	"fmt"
+	"route-planning/graph"
+	"strconv"
+)
+
+// ErrSparseNodeIDs is returned for edge lists whose numeric node IDs are so
+// sparse that the graph would mostly consist of isolated nodes. Such IDs have
+// to be numbered densely, see SNAPOptions.Compact and CSVOptions.StringIDs.
+var ErrSparseNodeIDs = errors.New("node IDs are too sparse")
+
+// Numeric node IDs are sparse if they result in more than minSparseNodes nodes
+// and more than sparseRatio nodes per ID in the file.
+const (
+	minSparseNodes = 1 << 20
+	sparseRatio    = 16
+)
+
+// nodeMapper turns the node IDs of edge lists into nodes.
+type nodeMapper struct {
+	// oneBased marks numeric IDs starting at 1.
+	oneBased bool
+	// dense numbers arbitrary IDs in the order of their first appearance.
+	dense bool
+
+	// n is the number of nodes, i.e. the largest node plus one.
+	n int
+	// mapped is the number of node IDs mapped.
+	mapped int
+	index  map[string]graph.Node
+	names  []string
+}
+
+func newNodeMapper(oneBased, dense bool) *nodeMapper {
+	return &nodeMapper{oneBased: oneBased, dense: dense, index: make(map[string]graph.Node)}
+}
+
+func (m *nodeMapper) node(id string) (graph.Node, error) {
+	if id == "" {
+		return 0, errors.New("missing node ID")
+	}
+
+	if m.dense {
+		v, ok := m.index[id]
+		if !ok {
+			v = graph.Node(len(m.names))
+			m.index[id] = v
+			m.names = append(m.names, id)
+			m.n++
+		}
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, err
+	}
+	if m.oneBased {
+		v--
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("node ID %s out of range", id)
+	}
+
+	m.mapped++
+	if v >= m.n {
+		m.n = v + 1
+	}
+	return graph.Node(v), nil
+}
+
+// checkSparse returns ErrSparseNodeIDs if the numeric IDs are sparse.
+func (m *nodeMapper) checkSparse() error {
+	if m.dense || m.n <= minSparseNodes || m.n/sparseRatio <= m.mapped {
+		return nil
+	}
+	return fmt.Errorf("%w: the largest ID results in %d nodes for %d IDs in the file", ErrSparseNodeIDs, m.n, m.mapped)
+}
+
+// rollback forgets the nodes registered since the mapper had n nodes, e.g.
+// for the first node of an edge whose second node is invalid.
+func (m *nodeMapper) rollback(n int) {
+	if m.dense {
+		for _, id := range m.names[n:] {
+			delete(m.index, id)
+		}
+		m.names = m.names[:n]
+	}
+	m.n = n
+}
+
+// ids returns the original ID of every node.
+func (m *nodeMapper) ids() []string {
+	if m.dense {
+		return m.names
+	}
+
+	offset := 0
+	if m.oneBased {
+		offset = 1
+	}
+
+	ids := make([]string, m.n)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i + offset)
+	}
+	return ids
+}
@@ -0,0 +1,41 @@
+package graphio
+
+import "runtime"
+
+// ParseOption configures how text inputs are parsed.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	workers int
+	lenient bool
+	// chunkSize is the approximate number of bytes parsed at once by a worker.
+	chunkSize int
+}
+
+func newParseOptions(opts []ParseOption) parseOptions {
+	o := parseOptions{workers: runtime.GOMAXPROCS(0), chunkSize: defaultChunkSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithWorkers sets the number of goroutines parsing the input in parallel.
+// Values below 1 select one worker per available CPU, the default.
+func WithWorkers(n int) ParseOption {
+	return func(o *parseOptions) {
+		if n < 1 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		o.workers = n
+	}
+}
+
+// WithLenient skips malformed data lines instead of aborting. Their errors
+// are available from the input, see LenientInput. Malformed headers are still
+// fatal.
+func WithLenient() ParseOption {
+	return func(o *parseOptions) {
+		o.lenient = true
+	}
+}
@@ -0,0 +1,452 @@
+package graphio
+
+import (
+	"fmt"
+	"route-planning/graph"
+	"strconv"
+	"strings"
+)
+
+// OSMProfile decides which OpenStreetMap ways are routable and how fast they
+// can be traversed.
+type OSMProfile struct {
+	Name string
+
+	// Speeds maps the highway types usable by the profile to their speed in
+	// km/h.
+	Speeds map[string]float64
+	// UseMaxSpeed enables reading the speed from the maxspeed tag. The speed
+	// never exceeds the speed of the highway type.
+	UseMaxSpeed bool
+
+	// AccessTags are the tags restricting access, most specific first.
+	AccessTags []string
+	// OnewayTags are the tags marking ways as oneway, most specific first.
+	// Oneway restrictions are ignored if empty.
+	OnewayTags []string
+}
+
+var CarProfile = OSMProfile{
+	Name: "car",
+	Speeds: map[string]float64{
+		"motorway":       130,
+		"motorway_link":  60,
+		"trunk":          100,
+		"trunk_link":     50,
+		"primary":        80,
+		"primary_link":   40,
+		"secondary":      70,
+		"secondary_link": 35,
+		"tertiary":       60,
+		"tertiary_link":  30,
+		"unclassified":   40,
+		"residential":    30,
+		"living_street":  7,
+		"service":        15,
+		"road":           30,
+	},
+	UseMaxSpeed: true,
+	AccessTags:  []string{"motorcar", "motor_vehicle", "vehicle", "access"},
+	OnewayTags:  []string{"oneway"},
+}
+
+var BikeProfile = OSMProfile{
+	Name: "bike",
+	Speeds: map[string]float64{
+		"primary":        15,
+		"primary_link":   15,
+		"secondary":      15,
+		"secondary_link": 15,
+		"tertiary":       15,
+		"tertiary_link":  15,
+		"unclassified":   15,
+		"residential":    15,
+		"living_street":  10,
+		"service":        12,
+		"road":           12,
+		"cycleway":       18,
+		"track":          12,
+		"path":           12,
+	},
+	AccessTags: []string{"bicycle", "vehicle", "access"},
+	OnewayTags: []string{"oneway:bicycle", "oneway"},
+}
+
+var FootProfile = OSMProfile{
+	Name: "foot",
+	Speeds: map[string]float64{
+		"primary":        5,
+		"primary_link":   5,
+		"secondary":      5,
+		"secondary_link": 5,
+		"tertiary":       5,
+		"tertiary_link":  5,
+		"unclassified":   5,
+		"residential":    5,
+		"living_street":  5,
+		"service":        5,
+		"road":           5,
+		"pedestrian":     5,
+		"footway":        5,
+		"path":           5,
+		"track":          5,
+		"cycleway":       5,
+		"steps":          3,
+	},
+	AccessTags: []string{"foot", "access"},
+}
+
+// OSMProfiles contains the predefined profiles by name.
+var OSMProfiles = map[string]OSMProfile{
+	CarProfile.Name:  CarProfile,
+	BikeProfile.Name: BikeProfile,
+	FootProfile.Name: FootProfile,
+}
+
+// OSMMetric is the edge cost computed for OpenStreetMap graphs.
+type OSMMetric int
+
+const (
+	// Distance is the length of an edge in meters.
+	Distance OSMMetric = iota
+	// TravelTime is the time needed to traverse an edge in seconds.
+	TravelTime
+)
+
+type onewayDirection int
+
+const (
+	bothDirections onewayDirection = iota
+	forwardOnly
+	backwardOnly
+)
+
+// routable returns whether the profile allows using a way with tags at all.
+func (p OSMProfile) routable(tags map[string]string) bool {
+	if _, ok := p.Speeds[tags["highway"]]; !ok {
+		return false
+	}
+
+	if tags["area"] == "yes" {
+		return false
+	}
+
+	for _, tag := range p.AccessTags {
+		value, ok := tags[tag]
+		if !ok {
+			continue
+		}
+
+		switch value {
+		case "no", "private", "agricultural", "forestry", "delivery":
+			return false
+		default:
+			return true
+		}
+	}
+
+	return true
+}
+
+// speed returns the speed in km/h on a way with tags.
+func (p OSMProfile) speed(tags map[string]string) float64 {
+	speed := p.Speeds[tags["highway"]]
+
+	if p.UseMaxSpeed {
+		if maxSpeed, ok := parseMaxSpeed(tags["maxspeed"]); ok && maxSpeed < speed {
+			speed = maxSpeed
+		}
+	}
+
+	return speed
+}
+
+// direction returns in which directions a way with tags may be traversed.
+func (p OSMProfile) direction(tags map[string]string) onewayDirection {
+	if len(p.OnewayTags) == 0 {
+		return bothDirections
+	}
+
+	for _, tag := range p.OnewayTags {
+		switch tags[tag] {
+		case "yes", "true", "1":
+			return forwardOnly
+		case "-1", "reverse":
+			return backwardOnly
+		case "no", "false", "0":
+			return bothDirections
+		}
+	}
+
+	if tags["junction"] == "roundabout" || tags["highway"] == "motorway" {
+		return forwardOnly
+	}
+
+	return bothDirections
+}
+
+// parseMaxSpeed parses the value of a maxspeed tag in km/h. Only the first of
+// multiple values is considered.
+func parseMaxSpeed(value string) (float64, bool) {
+	value = strings.TrimSpace(strings.Split(value, ";")[0])
+
+	switch {
+	case value == "":
+		return 0, false
+	case value == "walk":
+		return 5, true
+	case strings.HasSuffix(value, ":living_street"):
+		return 7, true
+	case strings.HasSuffix(value, ":urban"):
+		return 50, true
+	case strings.HasSuffix(value, ":rural"):
+		return 90, true
+	}
+
+	factor := 1.0
+	if strings.HasSuffix(value, "mph") {
+		factor = 1.609344
+		value = strings.TrimSpace(strings.TrimSuffix(value, "mph"))
+	}
+
+	speed, err := strconv.ParseFloat(value, 64)
+	if err != nil || speed <= 0 {
+		return 0, false
+	}
+
+	return speed * factor, true
+}
+
+// OSMWayInfo describes the way an edge of an OpenStreetMap graph belongs to.
+type OSMWayInfo struct {
+	WayID   int64
+	Highway string
+	Name    string
+	// Speed is the speed on the way in km/h.
+	Speed float64
+	// Length is the length of the edge in meters.
+	Length float64
+}
+
+// osmWay is a routable way while importing OpenStreetMap data.
+type osmWay struct {
+	id        int64
+	refs      []int64
+	highway   string
+	name      string
+	speed     float64
+	direction onewayDirection
+}
+
+// osmGraph is the routing graph built from OpenStreetMap data. info holds the
+// way information of every edge.
+type osmGraph struct {
+	edges  []graph.Edge
+	info   []OSMWayInfo
+	coords []graph.Coordinate
+}
+
+// osmGraphBuilder collects the routable ways and the coordinates of their
+// nodes and turns them into a graph. Ways are split at every node shared with
+// another way, all other nodes only contribute to the geometry.
+type osmGraphBuilder struct {
+	profile OSMProfile
+	metric  OSMMetric
+
+	ways []osmWay
+	// references counts the uses of a node up to 2, endpoints of ways are
+	// always counted as 2.
+	references map[int64]uint8
+	coords     map[int64]graph.Coordinate
+}
+
+func newOSMGraphBuilder(profile OSMProfile, metric OSMMetric) *osmGraphBuilder {
+	return &osmGraphBuilder{
+		profile:    profile,
+		metric:     metric,
+		references: make(map[int64]uint8),
+		coords:     make(map[int64]graph.Coordinate),
+	}
+}
+
+func (b *osmGraphBuilder) addWay(id int64, refs []int64, tags map[string]string) {
+	if len(refs) < 2 || !b.profile.routable(tags) {
+		return
+	}
+
+	for i, ref := range refs {
+		count := b.references[ref]
+		if count < 2 {
+			count++
+		}
+		if i == 0 || i == len(refs)-1 {
+			count = 2
+		}
+		b.references[ref] = count
+	}
+
+	b.ways = append(b.ways, osmWay{
+		id:        id,
+		refs:      refs,
+		highway:   tags["highway"],
+		name:      tags["name"],
+		speed:     b.profile.speed(tags),
+		direction: b.profile.direction(tags),
+	})
+}
+
+// needsNode returns whether the coordinate of a node is required.
+func (b *osmGraphBuilder) needsNode(id int64) bool {
+	_, ok := b.references[id]
+	return ok
+}
+
+func (b *osmGraphBuilder) addNode(id int64, c graph.Coordinate) {
+	if b.needsNode(id) {
+		b.coords[id] = c
+	}
+}
+
+func (b *osmGraphBuilder) build() (*osmGraph, error) {
+	if len(b.ways) == 0 {
+		return nil, fmt.Errorf("no routable ways for profile %q", b.profile.Name)
+	}
+
+	g := &osmGraph{}
+	nodes := make(map[int64]graph.Node)
+
+	node := func(id int64) graph.Node {
+		v, ok := nodes[id]
+		if !ok {
+			v = graph.Node(len(g.coords))
+			nodes[id] = v
+			g.coords = append(g.coords, b.coords[id])
+		}
+		return v
+	}
+
+	for _, way := range b.ways {
+		from := int64(-1)
+		length := 0.0
+
+		for i, ref := range way.refs {
+			c, ok := b.coords[ref]
+			if !ok {
+				// The node is missing from the extract, split the way.
+				from, length = -1, 0
+				continue
+			}
+
+			if i > 0 && from >= 0 {
+				length += b.coords[way.refs[i-1]].Distance(c)
+			}
+
+			isGraphNode := b.references[ref] >= 2 || i == len(way.refs)-1
+			if !isGraphNode {
+				if from < 0 {
+					from, length = ref, 0
+					node(ref)
+				}
+				continue
+			}
+
+			if from >= 0 && from != ref {
+				b.addEdges(g, way, node(from), node(ref), length)
+			}
+			from, length = ref, 0
+			node(ref)
+		}
+	}
+
+	return g, nil
+}
+
+func (b *osmGraphBuilder) addEdges(g *osmGraph, way osmWay, u, v graph.Node, length float64) {
+	cost := length
+	if b.metric == TravelTime {
+		cost = length / (way.speed / 3.6)
+	}
+
+	info := OSMWayInfo{
+		WayID:   way.id,
+		Highway: way.highway,
+		Name:    way.name,
+		Speed:   way.speed,
+		Length:  length,
+	}
+
+	if way.direction != backwardOnly {
+		g.edges = append(g.edges, graph.Edge{From: u, To: v, Cost: cost})
+		g.info = append(g.info, info)
+	}
+	if way.direction != forwardOnly {
+		g.edges = append(g.edges, graph.Edge{From: v, To: u, Cost: cost})
+		g.info = append(g.info, info)
+	}
+}
+
+// OSMInput provides the routing graph, the node coordinates and the way
+// information of every edge of OpenStreetMap data. The data is only parsed
+// once.
+type OSMInput interface {
+	GraphInput
+	CoordinateInput
+	LoadWayInfo() ([]OSMWayInfo, error)
+}
+
+// osmInput parses OpenStreetMap data lazily using parse.
+type osmInput struct {
+	profile OSMProfile
+	metric  OSMMetric
+	parse   func(*osmGraphBuilder) error
+
+	graph *osmGraph
+}
+
+func (oi *osmInput) load() (*osmGraph, error) {
+	if oi.graph != nil {
+		return oi.graph, nil
+	}
+
+	b := newOSMGraphBuilder(oi.profile, oi.metric)
+	if err := oi.parse(b); err != nil {
+		return nil, err
+	}
+
+	g, err := b.build()
+	if err != nil {
+		return nil, err
+	}
+
+	oi.graph = g
+	return g, nil
+}
+
+func (oi *osmInput) LoadGraph() ([]graph.Edge, int, error) {
+	g, err := oi.load()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return g.edges, len(g.coords), nil
+}
+
+func (oi *osmInput) LoadCoordinates() ([]graph.Coordinate, error) {
+	g, err := oi.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.coords, nil
+}
+
+// LoadWayInfo returns the way information of every edge in the order of the
+// edges returned by LoadGraph.
+func (oi *osmInput) LoadWayInfo() ([]OSMWayInfo, error) {
+	g, err := oi.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.info, nil
+}
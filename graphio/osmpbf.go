@@ -0,0 +1,494 @@
+package graphio
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"route-planning/graph"
+)
+
+// NewOSMPBFInput reads the routing graph for profile from an OpenStreetMap
+// .osm.pbf file. Only uncompressed and zlib compressed blobs are supported.
+func NewOSMPBFInput(file string, profile OSMProfile, metric OSMMetric) OSMInput {
+	return &osmInput{
+		profile: profile,
+		metric:  metric,
+		parse: func(b *osmGraphBuilder) error {
+			return parseOSMPBF(file, b)
+		},
+	}
+}
+
+// parseOSMPBF reads file twice: first all ways, then the coordinates of the
+// nodes referenced by routable ways.
+func parseOSMPBF(file string, b *osmGraphBuilder) error {
+	err := readOSMPBF(file, func(block *pbfPrimitiveBlock) error {
+		return block.ways(b.addWay)
+	})
+	if err != nil {
+		return fmt.Errorf("OSM PBF parsing failed: \n\t%w", err)
+	}
+
+	err = readOSMPBF(file, func(block *pbfPrimitiveBlock) error {
+		return block.nodes(b.addNode)
+	})
+	if err != nil {
+		return fmt.Errorf("OSM PBF parsing failed: \n\t%w", err)
+	}
+
+	return nil
+}
+
+// maxPBFBlobSize is the maximum size of a blob allowed by the PBF format.
+const maxPBFBlobSize = 32 * 1024 * 1024
+
+// readOSMPBF calls handle for every data block in file.
+func readOSMPBF(file string, handle func(*pbfPrimitiveBlock) error) error {
+	f, err := openFile(file)
+	if err != nil {
+		return fmt.Errorf("error opening OSM file %s: %w", file, err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+
+	for {
+		blobType, data, err := readPBFBlob(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch blobType {
+		case "OSMHeader":
+			if err := checkPBFHeader(data); err != nil {
+				return err
+			}
+		case "OSMData":
+			block, err := parsePBFPrimitiveBlock(data)
+			if err != nil {
+				return err
+			}
+			if err := handle(block); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readPBFBlob reads the next blob header and blob and returns the blob type
+// and the uncompressed blob data.
+func readPBFBlob(r io.Reader) (string, []byte, error) {
+	var headerSize uint32
+	if err := binary.Read(r, binary.BigEndian, &headerSize); err != nil {
+		return "", nil, err
+	}
+	if headerSize > maxPBFBlobSize {
+		return "", nil, fmt.Errorf("blob header size %d exceeds maximum", headerSize)
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return "", nil, fmt.Errorf("error reading blob header: %w", err)
+	}
+
+	var blobType string
+	var dataSize uint64
+	err := forEachPBFField(header, func(f pbfField) error {
+		switch f.number {
+		case 1:
+			blobType = string(f.data)
+		case 3:
+			dataSize = f.value
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("error parsing blob header: %w", err)
+	}
+	if dataSize > maxPBFBlobSize {
+		return "", nil, fmt.Errorf("blob size %d exceeds maximum", dataSize)
+	}
+
+	blob := make([]byte, dataSize)
+	if _, err := io.ReadFull(r, blob); err != nil {
+		return "", nil, fmt.Errorf("error reading blob: %w", err)
+	}
+
+	data, err := decodePBFBlob(blob)
+	if err != nil {
+		return "", nil, fmt.Errorf("error decoding %s blob: %w", blobType, err)
+	}
+
+	return blobType, data, nil
+}
+
+func decodePBFBlob(blob []byte) ([]byte, error) {
+	var data []byte
+	var rawSize uint64
+	compression := ""
+
+	err := forEachPBFField(blob, func(f pbfField) error {
+		switch f.number {
+		case 1:
+			data = f.data
+		case 2:
+			rawSize = f.value
+		case 3:
+			data, compression = f.data, "zlib"
+		case 4:
+			compression = "lzma"
+		case 6:
+			compression = "lz4"
+		case 7:
+			compression = "zstd"
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch compression {
+	case "":
+		return data, nil
+	case "zlib":
+		if rawSize > maxPBFBlobSize {
+			return nil, fmt.Errorf("raw blob size %d exceeds maximum", rawSize)
+		}
+
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		out := bytes.NewBuffer(make([]byte, 0, rawSize))
+		if _, err := io.Copy(out, io.LimitReader(zr, maxPBFBlobSize)); err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported blob compression %s", compression)
+	}
+}
+
+func checkPBFHeader(data []byte) error {
+	return forEachPBFField(data, func(f pbfField) error {
+		if f.number != 4 {
+			return nil
+		}
+
+		switch feature := string(f.data); feature {
+		case "OsmSchema-V0.6", "DenseNodes":
+			return nil
+		default:
+			return fmt.Errorf("unsupported required feature %q", feature)
+		}
+	})
+}
+
+type pbfPrimitiveBlock struct {
+	strings     [][]byte
+	groups      [][]byte
+	granularity int64
+	latOffset   int64
+	lonOffset   int64
+}
+
+func parsePBFPrimitiveBlock(data []byte) (*pbfPrimitiveBlock, error) {
+	block := &pbfPrimitiveBlock{granularity: 100}
+
+	err := forEachPBFField(data, func(f pbfField) error {
+		switch f.number {
+		case 1:
+			return forEachPBFField(f.data, func(s pbfField) error {
+				if s.number == 1 {
+					block.strings = append(block.strings, s.data)
+				}
+				return nil
+			})
+		case 2:
+			block.groups = append(block.groups, f.data)
+		case 17:
+			block.granularity = int64(f.value)
+		case 19:
+			block.latOffset = int64(f.value)
+		case 20:
+			block.lonOffset = int64(f.value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error parsing primitive block: %w", err)
+	}
+
+	return block, nil
+}
+
+func (pb *pbfPrimitiveBlock) coordinate(lat, lon int64) graph.Coordinate {
+	return graph.Coordinate{
+		Lat: 1e-9 * float64(pb.latOffset+pb.granularity*lat),
+		Lon: 1e-9 * float64(pb.lonOffset+pb.granularity*lon),
+	}
+}
+
+func (pb *pbfPrimitiveBlock) string(i uint64) (string, error) {
+	if i >= uint64(len(pb.strings)) {
+		return "", fmt.Errorf("string index %d out of range", i)
+	}
+	return string(pb.strings[i]), nil
+}
+
+func (pb *pbfPrimitiveBlock) tags(keys, vals []uint64) (map[string]string, error) {
+	if len(keys) != len(vals) {
+		return nil, errors.New("number of keys and values differ")
+	}
+
+	tags := make(map[string]string, len(keys))
+	for i := range keys {
+		k, err := pb.string(keys[i])
+		if err != nil {
+			return nil, err
+		}
+		v, err := pb.string(vals[i])
+		if err != nil {
+			return nil, err
+		}
+		tags[k] = v
+	}
+
+	return tags, nil
+}
+
+// ways calls handle for every way in the block.
+func (pb *pbfPrimitiveBlock) ways(handle func(id int64, refs []int64, tags map[string]string)) error {
+	for _, group := range pb.groups {
+		err := forEachPBFField(group, func(f pbfField) error {
+			if f.number != 3 {
+				return nil
+			}
+
+			var id int64
+			var keys, vals []uint64
+			var refs []int64
+
+			err := forEachPBFField(f.data, func(wf pbfField) error {
+				var err error
+				switch wf.number {
+				case 1:
+					id = int64(wf.value)
+				case 2:
+					keys, err = wf.packed(keys)
+				case 3:
+					vals, err = wf.packed(vals)
+				case 8:
+					var deltas []uint64
+					deltas, err = wf.packed(nil)
+					refs = decodeDeltas(refs, deltas)
+				}
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error parsing way: %w", err)
+			}
+
+			tags, err := pb.tags(keys, vals)
+			if err != nil {
+				return fmt.Errorf("error parsing tags of way %d: %w", id, err)
+			}
+
+			handle(id, refs, tags)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nodes calls handle for every node in the block, both plain and dense.
+func (pb *pbfPrimitiveBlock) nodes(handle func(id int64, c graph.Coordinate)) error {
+	for _, group := range pb.groups {
+		err := forEachPBFField(group, func(f pbfField) error {
+			switch f.number {
+			case 1:
+				return pb.plainNode(f.data, handle)
+			case 2:
+				return pb.denseNodes(f.data, handle)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pb *pbfPrimitiveBlock) plainNode(data []byte, handle func(id int64, c graph.Coordinate)) error {
+	var id, lat, lon int64
+	err := forEachPBFField(data, func(f pbfField) error {
+		switch f.number {
+		case 1:
+			id = zigzag(f.value)
+		case 8:
+			lat = zigzag(f.value)
+		case 9:
+			lon = zigzag(f.value)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error parsing node: %w", err)
+	}
+
+	handle(id, pb.coordinate(lat, lon))
+	return nil
+}
+
+func (pb *pbfPrimitiveBlock) denseNodes(data []byte, handle func(id int64, c graph.Coordinate)) error {
+	var ids, lats, lons []int64
+	err := forEachPBFField(data, func(f pbfField) error {
+		var values []uint64
+		var err error
+		switch f.number {
+		case 1:
+			values, err = f.packed(nil)
+			ids = decodeDeltas(ids, values)
+		case 8:
+			values, err = f.packed(nil)
+			lats = decodeDeltas(lats, values)
+		case 9:
+			values, err = f.packed(nil)
+			lons = decodeDeltas(lons, values)
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error parsing dense nodes: %w", err)
+	}
+
+	if len(ids) != len(lats) || len(ids) != len(lons) {
+		return errors.New("error parsing dense nodes: number of ids and coordinates differ")
+	}
+
+	for i, id := range ids {
+		handle(id, pb.coordinate(lats[i], lons[i]))
+	}
+
+	return nil
+}
+
+// decodeDeltas appends the values of the zigzag encoded deltas to values.
+func decodeDeltas(values []int64, deltas []uint64) []int64 {
+	var last int64
+	if len(values) > 0 {
+		last = values[len(values)-1]
+	}
+
+	for _, d := range deltas {
+		last += zigzag(d)
+		values = append(values, last)
+	}
+
+	return values
+}
+
+func zigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// Protocol buffer wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// pbfField is a single field of a protocol buffer message. value is set for
+// varint and fixed size fields, data for length-delimited fields.
+type pbfField struct {
+	number   int
+	wireType int
+	value    uint64
+	data     []byte
+}
+
+// packed appends the varints of a packed repeated field to values. Fields
+// which are not packed contain a single value.
+func (f pbfField) packed(values []uint64) ([]uint64, error) {
+	if f.wireType == wireVarint {
+		return append(values, f.value), nil
+	}
+
+	data := f.data
+	for len(data) > 0 {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("malformed packed varint")
+		}
+		values = append(values, v)
+		data = data[n:]
+	}
+
+	return values, nil
+}
+
+func forEachPBFField(data []byte, handle func(pbfField) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("malformed field key")
+		}
+		data = data[n:]
+
+		f := pbfField{number: int(key >> 3), wireType: int(key & 7)}
+
+		switch f.wireType {
+		case wireVarint:
+			f.value, n = binary.Uvarint(data)
+			if n <= 0 {
+				return fmt.Errorf("malformed varint in field %d", f.number)
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("truncated field %d", f.number)
+			}
+			f.value = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || size > uint64(len(data)-n) {
+				return fmt.Errorf("truncated field %d", f.number)
+			}
+			f.data = data[n : n+int(size)]
+			data = data[n+int(size):]
+		case wireFixed32:
+			if len(data) < 4 {
+				return fmt.Errorf("truncated field %d", f.number)
+			}
+			f.value = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		default:
+			return fmt.Errorf("unsupported wire type %d in field %d", f.wireType, f.number)
+		}
+
+		if err := handle(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+package graphio_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"testing"
+)
+
+// protoMessage is a minimal protocol buffer encoder for building test files.
+type protoMessage []byte
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func (m protoMessage) varint(field int, v uint64) protoMessage {
+	m = appendUvarint(m, uint64(field<<3))
+	return appendUvarint(m, v)
+}
+
+func (m protoMessage) bytes(field int, data []byte) protoMessage {
+	m = appendUvarint(m, uint64(field<<3|2))
+	m = appendUvarint(m, uint64(len(data)))
+	return append(m, data...)
+}
+
+func (m protoMessage) packed(field int, values []uint64) protoMessage {
+	var data []byte
+	for _, v := range values {
+		data = appendUvarint(data, v)
+	}
+	return m.bytes(field, data)
+}
+
+func zigzagDeltas(values []int64) []uint64 {
+	out := make([]uint64, len(values))
+	var last int64
+	for i, v := range values {
+		d := v - last
+		out[i] = uint64((d << 1) ^ (d >> 63))
+		last = v
+	}
+	return out
+}
+
+func pbfBlob(t *testing.T, blobType string, data []byte) []byte {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+
+	blob := protoMessage(nil).varint(2, uint64(len(data))).bytes(3, compressed.Bytes())
+	header := protoMessage(nil).bytes(1, []byte(blobType)).varint(3, uint64(len(blob)))
+
+	out := make([]byte, 4, 4+len(header)+len(blob))
+	binary.BigEndian.PutUint32(out, uint32(len(header)))
+	out = append(out, header...)
+	return append(out, blob...)
+}
+
+type testWay struct {
+	id   int64
+	refs []int64
+	tags [][2]string
+}
+
+// writeTestPBF writes nodes 1..len(coords) and ways into a PBF file.
+func writeTestPBF(t *testing.T, coords []graph.Coordinate, ways []testWay) string {
+	strings := []string{""}
+	index := map[string]uint64{}
+	str := func(s string) uint64 {
+		if i, ok := index[s]; ok {
+			return i
+		}
+		index[s] = uint64(len(strings))
+		strings = append(strings, s)
+		return index[s]
+	}
+
+	ids := make([]int64, len(coords))
+	lats := make([]int64, len(coords))
+	lons := make([]int64, len(coords))
+	for i, c := range coords {
+		ids[i] = int64(i + 1)
+		lats[i] = int64(math.Round(c.Lat * 1e7))
+		lons[i] = int64(math.Round(c.Lon * 1e7))
+	}
+
+	dense := protoMessage(nil).
+		packed(1, zigzagDeltas(ids)).
+		packed(8, zigzagDeltas(lats)).
+		packed(9, zigzagDeltas(lons))
+
+	group := protoMessage(nil).bytes(2, dense)
+
+	for _, w := range ways {
+		var keys, vals []uint64
+		for _, tag := range w.tags {
+			keys = append(keys, str(tag[0]))
+			vals = append(vals, str(tag[1]))
+		}
+		way := protoMessage(nil).
+			varint(1, uint64(w.id)).
+			packed(2, keys).
+			packed(3, vals).
+			packed(8, zigzagDeltas(w.refs))
+		group = group.bytes(3, way)
+	}
+
+	var table protoMessage
+	for _, s := range strings {
+		table = table.bytes(1, []byte(s))
+	}
+
+	block := protoMessage(nil).bytes(1, table).bytes(2, group)
+	header := protoMessage(nil).bytes(4, []byte("OsmSchema-V0.6")).bytes(4, []byte("DenseNodes"))
+
+	data := append(pbfBlob(t, "OSMHeader", header), pbfBlob(t, "OSMData", block)...)
+
+	file := filepath.Join(t.TempDir(), "test.osm.pbf")
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+var osmTestCoords = []graph.Coordinate{
+	{Lat: 52.5, Lon: 13.4},
+	{Lat: 52.501, Lon: 13.4},
+	{Lat: 52.502, Lon: 13.4},
+	{Lat: 52.503, Lon: 13.4},
+	{Lat: 52.501, Lon: 13.401},
+	{Lat: 52.503, Lon: 13.401},
+}
+
+var osmTestWays = []testWay{
+	{id: 10, refs: []int64{1, 2, 3, 4}, tags: [][2]string{{"highway", "residential"}, {"name", "Hauptstraße"}}},
+	{id: 11, refs: []int64{2, 5}, tags: [][2]string{{"highway", "primary"}, {"oneway", "yes"}, {"maxspeed", "50"}}},
+	{id: 12, refs: []int64{4, 6}, tags: [][2]string{{"highway", "footway"}}},
+	{id: 13, refs: []int64{5, 6}, tags: [][2]string{{"building", "yes"}}},
+}
+
+func roundCoordinates(coords []graph.Coordinate) []graph.Coordinate {
+	out := make([]graph.Coordinate, len(coords))
+	for i, c := range coords {
+		out[i] = graph.Coordinate{
+			Lat: math.Round(c.Lat*1e7) / 1e7,
+			Lon: math.Round(c.Lon*1e7) / 1e7,
+		}
+	}
+	return out
+}
+
+func TestOSMPBFInput(t *testing.T) {
+	file := writeTestPBF(t, osmTestCoords, osmTestWays)
+
+	in := graphio.NewOSMPBFInput(file, graphio.CarProfile, graphio.Distance)
+
+	edges, n, err := in.LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n != 4 {
+		t.Fatalf("expected 4 nodes, got %d", n)
+	}
+
+	coords, err := in.LoadCoordinates()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedCoords := []graph.Coordinate{osmTestCoords[0], osmTestCoords[1], osmTestCoords[3], osmTestCoords[4]}
+	if got := roundCoordinates(coords); !reflect.DeepEqual(got, expectedCoords) {
+		t.Errorf("expected coordinates %v, got %v", expectedCoords, got)
+	}
+
+	expected := []struct {
+		from, to graph.Node
+		length   float64
+	}{
+		{0, 1, osmTestCoords[0].Distance(osmTestCoords[1])},
+		{1, 0, osmTestCoords[0].Distance(osmTestCoords[1])},
+		{1, 2, osmTestCoords[1].Distance(osmTestCoords[2]) + osmTestCoords[2].Distance(osmTestCoords[3])},
+		{2, 1, osmTestCoords[1].Distance(osmTestCoords[2]) + osmTestCoords[2].Distance(osmTestCoords[3])},
+		{1, 3, osmTestCoords[1].Distance(osmTestCoords[4])},
+	}
+
+	if len(edges) != len(expected) {
+		t.Fatalf("expected %d edges, got %v", len(expected), edges)
+	}
+
+	for i, e := range expected {
+		got := edges[i]
+		if got.From != e.from || got.To != e.to || math.Abs(got.Cost-e.length) > 0.01 {
+			t.Errorf("edge %d: expected %d -> %d (%f), got %v", i, e.from, e.to, e.length, got)
+		}
+	}
+}
+
+func TestOSMPBFInputManyWaysAtNode(t *testing.T) {
+	coords := []graph.Coordinate{
+		{Lat: 52.5, Lon: 13.4},
+		{Lat: 52.501, Lon: 13.4},
+		{Lat: 52.499, Lon: 13.4},
+		{Lat: 52.5, Lon: 13.401},
+	}
+
+	// Node 1 is inside way 10 and the endpoint of 255 more ways, which must
+	// not overflow its reference count.
+	ways := []testWay{{id: 10, refs: []int64{2, 1, 3}, tags: [][2]string{{"highway", "residential"}}}}
+	for i := 0; i < 255; i++ {
+		ways = append(ways, testWay{id: int64(100 + i), refs: []int64{1, 4}, tags: [][2]string{{"highway", "residential"}}})
+	}
+
+	edges, n, err := graphio.NewOSMPBFInput(writeTestPBF(t, coords, ways), graphio.CarProfile, graphio.Distance).LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n != 4 || len(edges) != 4+2*255 {
+		t.Fatalf("expected 4 nodes and %d edges, got %d nodes and %d edges", 4+2*255, n, len(edges))
+	}
+
+	// Way 10 is split at node 1.
+	expected := []struct {
+		from, to graph.Node
+		length   float64
+	}{
+		{0, 1, coords[1].Distance(coords[0])},
+		{1, 0, coords[1].Distance(coords[0])},
+		{1, 2, coords[0].Distance(coords[2])},
+		{2, 1, coords[0].Distance(coords[2])},
+	}
+
+	for i, e := range expected {
+		got := edges[i]
+		if got.From != e.from || got.To != e.to || math.Abs(got.Cost-e.length) > 0.01 {
+			t.Errorf("edge %d: expected %d -> %d (%f), got %v", i, e.from, e.to, e.length, got)
+		}
+	}
+}
+
+func TestOSMPBFInputTravelTime(t *testing.T) {
+	file := writeTestPBF(t, osmTestCoords, osmTestWays)
+
+	edges, _, err := graphio.NewOSMPBFInput(file, graphio.CarProfile, graphio.TravelTime).LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The primary road is limited to 50 km/h by its maxspeed tag.
+	expected := osmTestCoords[1].Distance(osmTestCoords[4]) / (50 / 3.6)
+	if got := edges[len(edges)-1].Cost; math.Abs(got-expected) > 0.01 {
+		t.Errorf("expected travel time %f, got %f", expected, got)
+	}
+}
+
+func TestOSMPBFInputFootProfile(t *testing.T) {
+	file := writeTestPBF(t, osmTestCoords, osmTestWays)
+
+	edges, n, err := graphio.NewOSMPBFInput(file, graphio.FootProfile, graphio.Distance).LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Pedestrians use the footway and ignore the oneway tag.
+	if n != 5 || len(edges) != 8 {
+		t.Errorf("expected 5 nodes and 8 edges, got %d nodes and %v", n, edges)
+	}
+}
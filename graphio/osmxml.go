@@ -0,0 +1,113 @@
+package graphio
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"route-planning/graph"
+)
+
+// NewOSMXMLInput reads the routing graph for profile from an OpenStreetMap
+// .osm XML file.
+func NewOSMXMLInput(file string, profile OSMProfile, metric OSMMetric) OSMInput {
+	return &osmInput{
+		profile: profile,
+		metric:  metric,
+		parse: func(b *osmGraphBuilder) error {
+			return parseOSMXML(file, b)
+		},
+	}
+}
+
+// parseOSMXML reads file twice like parseOSMPBF as nodes precede the ways
+// referencing them.
+func parseOSMXML(file string, b *osmGraphBuilder) error {
+	if err := readOSMXML(file, nil, b.addWay); err != nil {
+		return fmt.Errorf("OSM XML parsing failed: \n\t%w", err)
+	}
+
+	if err := readOSMXML(file, b.addNode, nil); err != nil {
+		return fmt.Errorf("OSM XML parsing failed: \n\t%w", err)
+	}
+
+	return nil
+}
+
+type osmXMLTag struct {
+	Key   string `xml:"k,attr"`
+	Value string `xml:"v,attr"`
+}
+
+type osmXMLNode struct {
+	ID  int64   `xml:"id,attr"`
+	Lat float64 `xml:"lat,attr"`
+	Lon float64 `xml:"lon,attr"`
+}
+
+type osmXMLWay struct {
+	ID   int64 `xml:"id,attr"`
+	Refs []struct {
+		Ref int64 `xml:"ref,attr"`
+	} `xml:"nd"`
+	Tags []osmXMLTag `xml:"tag"`
+}
+
+// readOSMXML calls onNode for every node and onWay for every way in file.
+// Either handler may be nil to skip the element.
+func readOSMXML(file string, onNode func(int64, graph.Coordinate), onWay func(int64, []int64, map[string]string)) error {
+	f, err := openFile(file)
+	if err != nil {
+		return fmt.Errorf("error opening OSM file %s: %w", file, err)
+	}
+	defer f.Close()
+
+	dec := xml.NewDecoder(f)
+
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case start.Name.Local == "node" && onNode != nil:
+			var node osmXMLNode
+			if err := dec.DecodeElement(&node, &start); err != nil {
+				return fmt.Errorf("error parsing node: %w", err)
+			}
+			onNode(node.ID, graph.Coordinate{Lat: node.Lat, Lon: node.Lon})
+
+		case start.Name.Local == "way" && onWay != nil:
+			var way osmXMLWay
+			if err := dec.DecodeElement(&way, &start); err != nil {
+				return fmt.Errorf("error parsing way: %w", err)
+			}
+
+			refs := make([]int64, len(way.Refs))
+			for i, nd := range way.Refs {
+				refs[i] = nd.Ref
+			}
+
+			tags := make(map[string]string, len(way.Tags))
+			for _, tag := range way.Tags {
+				tags[tag.Key] = tag.Value
+			}
+
+			onWay(way.ID, refs, tags)
+
+		case start.Name.Local == "node", start.Name.Local == "way", start.Name.Local == "relation":
+			if err := dec.Skip(); err != nil {
+				return fmt.Errorf("error skipping %s: %w", start.Name.Local, err)
+			}
+		}
+	}
+}
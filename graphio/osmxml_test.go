@@ -0,0 +1,87 @@
+package graphio_test
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"route-planning/graphio"
+	"strings"
+	"testing"
+)
+
+func writeTestOSMXML(t *testing.T) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(`<osm version="0.6" generator="test">` + "\n")
+
+	for i, c := range osmTestCoords {
+		fmt.Fprintf(&sb, `  <node id="%d" lat="%.7f" lon="%.7f" version="1"/>`+"\n", i+1, c.Lat, c.Lon)
+	}
+
+	for _, w := range osmTestWays {
+		fmt.Fprintf(&sb, `  <way id="%d">`+"\n", w.id)
+		for _, ref := range w.refs {
+			fmt.Fprintf(&sb, `    <nd ref="%d"/>`+"\n", ref)
+		}
+		for _, tag := range w.tags {
+			fmt.Fprintf(&sb, `    <tag k="%s" v="%s"/>`+"\n", tag[0], tag[1])
+		}
+		sb.WriteString("  </way>\n")
+	}
+
+	sb.WriteString(`  <relation id="1"><member type="way" ref="10" role=""/></relation>` + "\n")
+	sb.WriteString("</osm>\n")
+
+	file := filepath.Join(t.TempDir(), "test.osm")
+	if err := os.WriteFile(file, []byte(sb.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func TestOSMXMLInputMatchesPBF(t *testing.T) {
+	xmlInput := graphio.NewOSMXMLInput(writeTestOSMXML(t), graphio.CarProfile, graphio.TravelTime)
+	pbfInput := graphio.NewOSMPBFInput(writeTestPBF(t, osmTestCoords, osmTestWays), graphio.CarProfile, graphio.TravelTime)
+
+	xmlEdges, xmlN, err := xmlInput.LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pbfEdges, pbfN, err := pbfInput.LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if xmlN != pbfN || len(xmlEdges) != len(pbfEdges) {
+		t.Fatalf("expected %d nodes and %d edges, got %d nodes and %d edges", pbfN, len(pbfEdges), xmlN, len(xmlEdges))
+	}
+
+	for i := range xmlEdges {
+		x, p := xmlEdges[i], pbfEdges[i]
+		if x.From != p.From || x.To != p.To || math.Abs(x.Cost-p.Cost) > 1e-3 {
+			t.Errorf("edge %d: expected %v, got %v", i, p, x)
+		}
+	}
+
+	info, err := xmlInput.LoadWayInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ways []int64
+	for _, wi := range info {
+		ways = append(ways, wi.WayID)
+	}
+
+	if expected := []int64{10, 10, 10, 10, 11}; !reflect.DeepEqual(ways, expected) {
+		t.Errorf("expected ways %v, got %v", expected, ways)
+	}
+
+	if info[0].Name != "Hauptstraße" || info[0].Highway != "residential" || info[4].Speed != 50 {
+		t.Errorf("unexpected way info %+v", info)
+	}
+}
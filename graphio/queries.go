@@ -0,0 +1,209 @@
+package graphio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"route-planning/graph"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type QueryKind int
+
+const (
+	// SingleSource queries ask for the distances from a source to all nodes.
+	SingleSource QueryKind = iota
+	// PointToPoint queries ask for the distance between two nodes.
+	PointToPoint
+)
+
+func (k QueryKind) String() string {
+	switch k {
+	case SingleSource:
+		return "ss"
+	case PointToPoint:
+		return "p2p"
+	default:
+		return fmt.Sprintf("QueryKind(%d)", int(k))
+	}
+}
+
+// Query is a shortest path query. Target is only set for point-to-point
+// queries.
+type Query struct {
+	Source graph.Node
+	Target graph.Node
+}
+
+type QuerySet struct {
+	Kind    QueryKind
+	Queries []Query
+}
+
+type QueryInput interface {
+	LoadQueries() (QuerySet, error)
+}
+
+type dimacsQueryInput struct {
+	file string
+}
+
+// NewDIMACSQueryInput reads DIMACS challenge 9 query files, i.e. single-source
+// (.ss) files with 'p aux sp ss {k}' and 's {s}' lines or point-to-point
+// (.p2p) files with 'p aux sp p2p {k}' and 'q {s} {t}' lines.
+func NewDIMACSQueryInput(file string) QueryInput {
+	return &dimacsQueryInput{file: file}
+}
+
+func (dqi dimacsQueryInput) LoadQueries() (QuerySet, error) {
+	f, err := openFile(dqi.file)
+	if err != nil {
+		return QuerySet{}, fmt.Errorf("error opening query file %s: %w", dqi.file, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	kind, k, err := parseQueryProblemLine(scanner)
+	if err != nil {
+		return QuerySet{}, fmt.Errorf("DIMACS query parsing failed: \n\t%w", err)
+	}
+
+	queries, err := parseQueryLines(scanner, kind, k)
+	if err != nil {
+		return QuerySet{}, fmt.Errorf("DIMACS query parsing failed: \n\t%w", err)
+	}
+
+	return QuerySet{Kind: kind, Queries: queries}, nil
+}
+
+func parseQueryProblemLine(scanner *bufio.Scanner) (QueryKind, int, error) {
+	problemLine := nextRelevantLine(scanner)
+	fields := strings.Fields(problemLine)
+	if len(fields) != 5 || fields[0] != "p" || fields[1] != "aux" || fields[2] != "sp" {
+		return 0, 0, fmt.Errorf("expected problem line of format 'p aux sp {ss|p2p} {k}', but got %q", problemLine)
+	}
+
+	var kind QueryKind
+	switch fields[3] {
+	case "ss":
+		kind = SingleSource
+	case "p2p":
+		kind = PointToPoint
+	default:
+		return 0, 0, fmt.Errorf("unsupported query type %q", fields[3])
+	}
+
+	k, err := strconv.Atoi(fields[4])
+	if err != nil {
+		return 0, 0, fmt.Errorf("error parsing {k} in problem line: %w", err)
+	}
+	if k < 0 {
+		return 0, 0, fmt.Errorf("negative {k} %d in problem line", k)
+	}
+
+	return kind, k, nil
+}
+
+func parseQueryLines(scanner *bufio.Scanner, kind QueryKind, k int) ([]Query, error) {
+	descriptor, numFields := "s", 2
+	if kind == PointToPoint {
+		descriptor, numFields = "q", 3
+	}
+
+	queries := make([]Query, 0, preallocated(k))
+
+	for line := nextRelevantLine(scanner); line != ""; line = nextRelevantLine(scanner) {
+		fields := strings.Fields(line)
+		if len(fields) != numFields || fields[0] != descriptor {
+			return nil, fmt.Errorf("expected %s query line but got %q", kind, line)
+		}
+
+		s, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing {s} of query line %q: %w", line, err)
+		}
+
+		query := Query{Source: graph.Node(s - 1)}
+
+		if kind == PointToPoint {
+			t, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("error parsing {t} of query line %q: %w", line, err)
+			}
+			query.Target = graph.Node(t - 1)
+		}
+
+		queries = append(queries, query)
+	}
+
+	if len(queries) != k {
+		return nil, fmt.Errorf("expected %d queries but got %d", k, len(queries))
+	}
+
+	return queries, nil
+}
+
+// QueryResult holds the answer to a query. For single-source queries Cost is
+// the sum of all finite distances from the source.
+type QueryResult struct {
+	Query Query
+	Cost  float64
+}
+
+type QueryResults struct {
+	Kind   QueryKind
+	Solver string
+
+	GraphFile string
+	QueryFile string
+
+	Nodes   int
+	Edges   int
+	MinCost float64
+	MaxCost float64
+
+	AverageTime time.Duration
+
+	Results []QueryResult
+}
+
+// WriteDIMACSResults writes results in the format of the DIMACS challenge 9
+// result files:
+//
+//	p res sp {ss|p2p} {solver}
+//	f {graph file} {query file}
+//	g {n} {m} {min arc cost} {max arc cost}
+//	t {average time per query in ms}
+//	d {s} {checksum}        for single-source queries
+//	d {s} {t} {distance}    for point-to-point queries
+//
+// Unreachable targets are reported with a distance of "inf".
+func WriteDIMACSResults(w io.Writer, res QueryResults) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "p res sp %s %s\n", res.Kind, res.Solver)
+	fmt.Fprintf(bw, "f %s %s\n", res.GraphFile, res.QueryFile)
+	fmt.Fprintf(bw, "g %d %d %s %s\n", res.Nodes, res.Edges, formatCost(res.MinCost), formatCost(res.MaxCost))
+	fmt.Fprintf(bw, "t %.6f\n", float64(res.AverageTime)/float64(time.Millisecond))
+
+	for _, r := range res.Results {
+		if res.Kind == PointToPoint {
+			fmt.Fprintf(bw, "d %d %d %s\n", r.Query.Source+1, r.Query.Target+1, formatCost(r.Cost))
+		} else {
+			fmt.Fprintf(bw, "d %d %s\n", r.Query.Source+1, formatCost(r.Cost))
+		}
+	}
+
+	return bw.Flush()
+}
+
+func formatCost(c float64) string {
+	if math.IsInf(c, 0) {
+		return "inf"
+	}
+	return strconv.FormatFloat(c, 'f', -1, 64)
+}
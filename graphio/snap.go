@@ -0,0 +1,219 @@
+package graphio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"route-planning/graph"
+	"sort"
+	"strconv"
+	"unicode"
+)
+
+// SNAPOptions describes plain edge lists as distributed by SNAP and
+// networkrepository.
+type SNAPOptions struct {
+	// Directed marks every line as a directed edge. Otherwise edges can be
+	// traversed in both directions.
+	Directed bool
+	// OneBased marks node IDs starting at 1, as used by networkrepository.
+	OneBased bool
+	// Compact numbers the node IDs in the order of their first appearance,
+	// which avoids isolated nodes for sparse IDs.
+	Compact bool
+}
+
+// SNAPInput reads plain edge lists. The data is only parsed once.
+type SNAPInput interface {
+	GraphInput
+	UndirectedGraphInput
+	// LoadNodeIDs returns the original ID of every node.
+	LoadNodeIDs() ([]string, error)
+}
+
+type snapInput struct {
+	src   *source
+	opts  SNAPOptions
+	parse parseOptions
+
+	graph   *snapGraph
+	skipped []*ParseError
+}
+
+// snapGraph holds the edges as listed in the file.
+type snapGraph struct {
+	edges []graph.Edge
+	n     int
+	ids   []string
+}
+
+// NewSNAPInput reads edge lists with one edge 'u v [w]' per line. Values may
+// be separated by whitespace or commas, further columns are ignored and lines
+// starting with '#' or '%' are comments. Edges without weight cost 1. The
+// lines are parsed in parallel, see WithWorkers, and malformed ones can be
+// skipped, see WithLenient.
+func NewSNAPInput(file string, opts SNAPOptions, parseOpts ...ParseOption) SNAPInput {
+	return &snapInput{src: fileSource(file), opts: opts, parse: newParseOptions(parseOpts)}
+}
+
+func NewSNAPInputFromReader(r io.Reader, opts SNAPOptions, parseOpts ...ParseOption) SNAPInput {
+	return &snapInput{src: readerSource(r), opts: opts, parse: newParseOptions(parseOpts)}
+}
+
+func (si *snapInput) LoadGraph() ([]graph.Edge, int, error) {
+	g, err := si.load()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if si.opts.Directed {
+		return g.edges, g.n, nil
+	}
+
+	edges := make([]graph.Edge, 0, 2*len(g.edges))
+	for _, e := range g.edges {
+		edges = append(edges, e)
+		if e.From != e.To {
+			edges = append(edges, e.Reverted())
+		}
+	}
+
+	return edges, g.n, nil
+}
+
+// LoadUndirectedGraph returns every edge once or ErrDirected if the edge list
+// is directed.
+func (si *snapInput) LoadUndirectedGraph() ([]graph.Edge, int, error) {
+	if si.opts.Directed {
+		return nil, 0, ErrDirected
+	}
+
+	g, err := si.load()
+	if err != nil {
+		return nil, 0, err
+	}
+	return g.edges, g.n, nil
+}
+
+func (si *snapInput) LoadNodeIDs() ([]string, error) {
+	g, err := si.load()
+	if err != nil {
+		return nil, err
+	}
+	return g.ids, nil
+}
+
+func (si *snapInput) SkippedLines() []*ParseError {
+	return si.skipped
+}
+
+func (si *snapInput) load() (*snapGraph, error) {
+	if si.graph != nil {
+		return si.graph, nil
+	}
+
+	f, err := si.src.open()
+	if err != nil {
+		return nil, fmt.Errorf("error opening graph file %s: %w", si.src, err)
+	}
+	defer f.Close()
+
+	errs := &lineErrors{src: si.src, lenient: si.parse.lenient}
+	g, err := parseSNAP(bufio.NewReader(f), si.opts, si.parse, errs)
+	si.skipped = errs.skipped
+	if err != nil {
+		return nil, fmt.Errorf("edge list parsing failed: \n\t%w", err)
+	}
+
+	si.graph = g
+	return g, nil
+}
+
+// snapRecord is an edge before mapping its node IDs.
+type snapRecord struct {
+	from, to field
+	cost     float64
+	line     int
+}
+
+// parseSNAP parses the lines in parallel. The node IDs are mapped in input
+// order afterwards, so that compact numbering is deterministic.
+func parseSNAP(r *bufio.Reader, opts SNAPOptions, parsing parseOptions, errs *lineErrors) (*snapGraph, error) {
+	g := &snapGraph{}
+	nodes := newNodeMapper(opts.OneBased, opts.Compact)
+
+	err := parseChunks(r, 1, parsing, errs, parseSNAPLines, func(value interface{}) error {
+		for _, record := range value.([]snapRecord) {
+			n := nodes.n
+			from, err := nodes.node(record.from.text)
+			if err != nil {
+				if err := errs.skip(parseErrorf(record.line, record.from.column, "invalid node: %w", err)); err != nil {
+					return err
+				}
+				continue
+			}
+			to, err := nodes.node(record.to.text)
+			if err != nil {
+				nodes.rollback(n)
+				if err := errs.skip(parseErrorf(record.line, record.to.column, "invalid node: %w", err)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			g.edges = append(g.edges, graph.Edge{From: from, To: to, Cost: record.cost})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalid nodes are only detected while merging, after the other skipped
+	// lines of their chunk.
+	sort.SliceStable(errs.skipped, func(i, j int) bool {
+		return errs.skipped[i].Line < errs.skipped[j].Line
+	})
+
+	if err := nodes.checkSparse(); err != nil {
+		return nil, err
+	}
+
+	g.n = nodes.n
+	g.ids = nodes.ids()
+
+	return g, nil
+}
+
+// parseSNAPLines parses a chunk of lines into a []snapRecord.
+func parseSNAPLines(data []byte, line int, errs *lineErrors) (interface{}, error) {
+	var records []snapRecord
+
+	err := forEachLine(data, line, func(text []byte, line int) error {
+		fields := splitFields(string(text), func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+
+		if len(fields) == 0 || fields[0].text[0] == '#' || fields[0].text[0] == '%' {
+			return nil
+		}
+
+		if len(fields) < 2 {
+			return errs.skip(parseErrorf(line, 0, "expected edge 'u v [w]' but got %q", text))
+		}
+
+		record := snapRecord{from: fields[0], to: fields[1], cost: 1, line: line}
+		if len(fields) > 2 {
+			cost, err := strconv.ParseFloat(fields[2].text, 64)
+			if err != nil {
+				return errs.skip(parseErrorf(line, fields[2].column, "invalid weight: %w", err))
+			}
+			record.cost = cost
+		}
+
+		records = append(records, record)
+		return nil
+	})
+
+	return records, err
+}
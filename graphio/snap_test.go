@@ -0,0 +1,86 @@
+package graphio_test
+
+import (
+	"errors"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"strings"
+	"testing"
+)
+
+func TestSNAPInput(t *testing.T) {
+	data := "# Nodes: 3 Edges: 2\n% networkrepository comment\n1 2 0.5\n\n2,3\n3\t3 7 1234\n"
+	in := graphio.NewSNAPInput(writeTestFile(t, "graph.edges", data), graphio.SNAPOptions{OneBased: true})
+
+	edges, n, err := in.LoadUndirectedGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []graph.Edge{{From: 0, To: 1, Cost: 0.5}, {From: 1, To: 2, Cost: 1}, {From: 2, To: 2, Cost: 7}}
+	if n != 3 || !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected 3 nodes and %v, got %d nodes and %v", expected, n, edges)
+	}
+
+	edges, _, err = in.LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Self-loops are only contained once.
+	expected = []graph.Edge{expected[0], expected[0].Reverted(), expected[1], expected[1].Reverted(), expected[2]}
+	if !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected %v, got %v", expected, edges)
+	}
+}
+
+func TestSNAPInputDirectedCompact(t *testing.T) {
+	data := "1000 7\n7 42\n"
+	in := graphio.NewSNAPInputFromReader(strings.NewReader(data), graphio.SNAPOptions{Directed: true, Compact: true})
+
+	if _, _, err := in.LoadUndirectedGraph(); !errors.Is(err, graphio.ErrDirected) {
+		t.Fatalf("expected ErrDirected, got %v", err)
+	}
+
+	edges, n, err := in.LoadGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []graph.Edge{{From: 0, To: 1, Cost: 1}, {From: 1, To: 2, Cost: 1}}
+	if n != 3 || !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected 3 nodes and %v, got %d nodes and %v", expected, n, edges)
+	}
+
+	ids, err := in.LoadNodeIDs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"1000", "7", "42"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected node IDs %v, got %v", expected, ids)
+	}
+}
+
+func TestSNAPInputInvalid(t *testing.T) {
+	for _, data := range []string{"1\n", "1 x\n", "1 2 x\n", "0 1\n"} {
+		_, _, err := graphio.NewSNAPInputFromReader(strings.NewReader(data), graphio.SNAPOptions{OneBased: true}).LoadGraph()
+		if err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+}
+
+func TestSNAPInputSparseIDs(t *testing.T) {
+	data := "1 4000000000\n2 3\n"
+
+	_, _, err := graphio.NewSNAPInputFromReader(strings.NewReader(data), graphio.SNAPOptions{}).LoadGraph()
+	if !errors.Is(err, graphio.ErrSparseNodeIDs) {
+		t.Errorf("expected ErrSparseNodeIDs, got %v", err)
+	}
+
+	_, n, err := graphio.NewSNAPInputFromReader(strings.NewReader(data), graphio.SNAPOptions{Compact: true}).LoadGraph()
+	if err != nil || n != 4 {
+		t.Errorf("expected 4 compact nodes, got %d (%v)", n, err)
+	}
+}
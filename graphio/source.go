@@ -0,0 +1,58 @@
+package graphio
+
+import (
+	"errors"
+	"io"
+)
+
+// errConsumed is returned when reading an input backed by an io.Reader again.
+var errConsumed = errors.New("input can only be read once")
+
+// source provides the data of an input either from a file, which can be read
+// any number of times, or from an io.Reader, which can be read only once.
+type source struct {
+	file     string
+	r        io.Reader
+	consumed bool
+}
+
+func fileSource(file string) *source {
+	return &source{file: file}
+}
+
+func readerSource(r io.Reader) *source {
+	return &source{r: r}
+}
+
+// rereadable returns whether the data can be read more than once.
+func (s *source) rereadable() bool {
+	return s.r == nil
+}
+
+// String returns the file name or a placeholder for readers to be used in
+// error messages.
+func (s *source) String() string {
+	if s.r != nil {
+		return "<reader>"
+	}
+	return s.file
+}
+
+// open returns the decompressed data. Readers are consumed by the first call.
+func (s *source) open() (io.ReadCloser, error) {
+	if s.rereadable() {
+		return openFile(s.file)
+	}
+
+	if s.consumed {
+		return nil, errConsumed
+	}
+	s.consumed = true
+
+	r, err := decompress(s.r)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(r), nil
+}
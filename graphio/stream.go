@@ -0,0 +1,115 @@
+package graphio
+
+import (
+	"fmt"
+	"route-planning/graph"
+)
+
+// EdgeStreamer is implemented by inputs which can provide their edges one by
+// one in the order of LoadGraph instead of returning them all at once.
+type EdgeStreamer interface {
+	// StreamEdges calls nodes with the number of nodes declared in the
+	// header and then visit for every edge. It returns the number of nodes.
+	// Edges with nodes outside of the declared range are malformed and
+	// result in a ParseError. Errors returned by visit abort the parsing.
+	StreamEdges(nodes func(n int), visit func(graph.Edge) error) (int, error)
+}
+
+// lineStreamer is implemented by streamers which know the line of every edge
+// in their file.
+type lineStreamer interface {
+	streamEdgeLines(nodes func(n int), visit func(e graph.Edge, line int) error) (int, error)
+	file() string
+}
+
+// streamEdgeLines streams the edges of s with their lines, which are 0 if s
+// does not know them.
+func streamEdgeLines(s EdgeStreamer, nodes func(n int), visit func(e graph.Edge, line int) error) (int, error) {
+	if ls, ok := s.(lineStreamer); ok {
+		return ls.streamEdgeLines(nodes, visit)
+	}
+	return s.StreamEdges(nodes, func(e graph.Edge) error { return visit(e, 0) })
+}
+
+// rereadableInput is implemented by inputs which know whether their data can
+// be read more than once.
+type rereadableInput interface {
+	rereadable() bool
+}
+
+// LoadCSR loads the graph of in in CSR format. Inputs which can stream their
+// edges repeatedly are read twice, counting the edges of every node in the
+// first pass and filling the arrays in the second, so that the edges are
+// never held in memory twice. Other inputs are loaded with LoadGraph.
+//
+// The edges are validated like ValidateEdges does. If there are problems, the
+// graph is nil and the problems are returned. Streamed inputs report edges
+// with nodes out of range as ParseError instead, as the graph is sized before
+// the edges are read.
+func LoadCSR(in GraphInput) (*graph.CSR, []Problem, error) {
+	streamer, ok := in.(EdgeStreamer)
+	if ri, rereadable := in.(rereadableInput); !ok || !rereadable || !ri.rereadable() {
+		edges, n, err := in.LoadGraph()
+		if err != nil {
+			return nil, nil, err
+		}
+		if problems := ValidateEdges(edges, n); len(problems) > 0 {
+			return nil, problems, nil
+		}
+		g, err := graph.NewCSR(edges, n)
+		return g, nil, err
+	}
+
+	var b *graph.CSRBuilder
+	var sizeErr error
+
+	n, err := streamer.StreamEdges(
+		func(n int) { b, sizeErr = graph.NewCSRBuilder(n) },
+		func(e graph.Edge) error {
+			if sizeErr != nil {
+				return sizeErr
+			}
+			return b.Count(e.From)
+		},
+	)
+	if err == nil {
+		err = sizeErr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b.Allocate()
+
+	file := ""
+	if ls, ok := streamer.(lineStreamer); ok {
+		file = ls.file()
+	}
+
+	var problems []Problem
+	i := 0
+	_, err = streamEdgeLines(streamer, func(int) {}, func(e graph.Edge, line int) error {
+		count := len(problems)
+		problems = validateEdge(problems, Problem{Edge: i, File: file, Line: line}, e, n)
+		i++
+
+		if len(problems) > count {
+			return nil
+		}
+		return b.Add(e)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading the edges again, was the input modified?: %w", err)
+	}
+
+	if len(problems) > 0 {
+		return nil, problems, nil
+	}
+
+	g, err := b.Build()
+	if err != nil {
+		return nil, nil, fmt.Errorf("the input was modified while reading: %w", err)
+	}
+
+	return g, nil, nil
+}
@@ -0,0 +1,94 @@
+package graphio_test
+
+import (
+	"errors"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"strings"
+	"testing"
+)
+
+func TestLoadCSR(t *testing.T) {
+	inputs := map[string]graphio.GraphInput{
+		"dimacs": graphio.NewDIMANCSInput(writeTestFile(t, "graph.gr", "p sp 3 3\na 3 1 4\na 1 2 1\na 1 3 2\n")),
+		"mtx":    graphio.NewMTXInput(writeTestFile(t, "graph.mtx", "%%MatrixMarket matrix coordinate integer general\n3 3 3\n3 1 4\n1 2 1\n1 3 2\n")),
+		// Readers cannot be streamed twice and are loaded at once.
+		"reader": graphio.NewDIMACSInputFromReader(strings.NewReader("p sp 3 3\na 3 1 4\na 1 2 1\na 1 3 2\n")),
+	}
+
+	expected := newCSR(t, []graph.Edge{
+		{From: 2, To: 0, Cost: 4},
+		{From: 0, To: 1, Cost: 1},
+		{From: 0, To: 2, Cost: 2},
+	}, 3)
+
+	for name, in := range inputs {
+		g, problems, err := graphio.LoadCSR(in)
+		if err != nil || len(problems) > 0 {
+			t.Errorf("%s: unexpected error: %v %v", name, err, problems)
+			continue
+		}
+
+		if !reflect.DeepEqual(g, expected) {
+			t.Errorf("%s: expected %v, got %v", name, expected, g)
+		}
+	}
+}
+
+func TestLoadCSRProblems(t *testing.T) {
+	file := writeTestFile(t, "graph.gr", "p sp 2 3\na 1 2 1\na 2 2 0\n\na 2 1 -1\n")
+
+	g, problems, err := graphio.LoadCSR(graphio.NewDIMANCSInput(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []graphio.Problem{
+		{Edge: 2, File: file, Line: 5, Message: "cost -1 is negative"},
+	}
+	if g != nil || !reflect.DeepEqual(problems, expected) {
+		t.Errorf("expected no graph and %v, got %v and %v", expected, g, problems)
+	}
+}
+
+func TestLoadCSRNodesOutOfRange(t *testing.T) {
+	tests := []struct {
+		name         string
+		in           graphio.GraphInput
+		line, column int
+	}{
+		{
+			name: "dimacs",
+			in:   graphio.NewDIMANCSInput(writeTestFile(t, "graph.gr", "p sp 2 2\na 1 2 1\na 3 1 1\n")),
+			line: 3, column: 3,
+		},
+		{
+			name: "mtx",
+			in:   graphio.NewMTXInput(writeTestFile(t, "graph.mtx", "%%MatrixMarket matrix coordinate pattern general\n2 2 2\n1 2\n2 1000000000\n")),
+			line: 4, column: 3,
+		},
+	}
+
+	for _, test := range tests {
+		var pe *graphio.ParseError
+		if _, _, err := graphio.LoadCSR(test.in); !errors.As(err, &pe) {
+			t.Errorf("%s: expected a ParseError, got %v", test.name, err)
+			continue
+		}
+
+		if pe.Line != test.line || pe.Column != test.column {
+			t.Errorf("%s: expected error at %d:%d, got %v", test.name, test.line, test.column, pe)
+		}
+	}
+
+	// Lenient inputs skip the edge in both passes.
+	in := graphio.NewDIMANCSInput(writeTestFile(t, "graph.gr", "p sp 2 2\na 1 2 1\na 2 3 1\n"), graphio.WithLenient())
+	g, problems, err := graphio.LoadCSR(in)
+	if err != nil || len(problems) > 0 {
+		t.Fatalf("unexpected error: %v %v", err, problems)
+	}
+	if expected := newCSR(t, []graph.Edge{{From: 0, To: 1, Cost: 1}}, 2); !reflect.DeepEqual(g, expected) {
+		t.Errorf("expected %v, got %v", expected, g)
+	}
+}
@@ -0,0 +1,117 @@
+package graphio
+
+import (
+	"fmt"
+	"math"
+	"route-planning/graph"
+)
+
+// Problem describes an inconsistency found while validating a graph input.
+type Problem struct {
+	// Edge is the position of the offending edge in the order returned by
+	// the input or -1 if the problem does not concern a single edge.
+	Edge int
+	// File and Line locate the offending edge in the input. Line is 0 if the
+	// input does not know the lines of its edges.
+	File    string
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	switch {
+	case p.Edge < 0:
+		return p.Message
+	case p.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+	}
+	return fmt.Sprintf("edge #%d: %s", p.Edge+1, p.Message)
+}
+
+// validationGraph is a graph loaded for validation.
+type validationGraph struct {
+	edges []graph.Edge
+	// lines is the line of every edge in file. Entries of symmetric matrices
+	// and METIS neighbours result in two edges of the same line.
+	lines []int
+	file  string
+	n     int
+	// m is the number of edges the header declares.
+	m int
+}
+
+// validationInput is implemented by inputs which declare the number of edges
+// in a header. loadForValidation loads the graph without checking the data
+// against the header.
+type validationInput interface {
+	loadForValidation() (*validationGraph, error)
+}
+
+// Validate loads the graph from in and returns all problems found. The
+// returned error is only set if the input could not be loaded at all.
+func Validate(in GraphInput) ([]Problem, error) {
+	vi, ok := in.(validationInput)
+	if !ok {
+		edges, n, err := in.LoadGraph()
+		if err != nil {
+			return nil, err
+		}
+		return ValidateEdges(edges, n), nil
+	}
+
+	g, err := vi.loadForValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	if len(g.edges) != g.m {
+		problems = append(problems, Problem{
+			Edge:    -1,
+			Message: fmt.Sprintf("header declares %d edges but data contains %d", g.m, len(g.edges)),
+		})
+	}
+
+	for i, e := range g.edges {
+		problems = validateEdge(problems, Problem{Edge: i, File: g.file, Line: g.lines[i]}, e, g.n)
+	}
+
+	return problems, nil
+}
+
+// ValidateEdges checks that all edges connect nodes in [0, n) and have a
+// finite, non-negative cost.
+func ValidateEdges(edges []graph.Edge, n int) []Problem {
+	var problems []Problem
+
+	for i, e := range edges {
+		problems = validateEdge(problems, Problem{Edge: i}, e, n)
+	}
+
+	return problems
+}
+
+// validateEdge appends the problems of the edge e to problems. The problems
+// are located like at.
+func validateEdge(problems []Problem, at Problem, e graph.Edge, n int) []Problem {
+	if e.From < 0 || int(e.From) >= n {
+		at.Message = fmt.Sprintf("source node %d out of range [0, %d)", e.From, n)
+		problems = append(problems, at)
+	}
+
+	if e.To < 0 || int(e.To) >= n {
+		at.Message = fmt.Sprintf("target node %d out of range [0, %d)", e.To, n)
+		problems = append(problems, at)
+	}
+
+	switch {
+	case math.IsNaN(e.Cost) || math.IsInf(e.Cost, 0):
+		at.Message = fmt.Sprintf("cost %g is not finite", e.Cost)
+		problems = append(problems, at)
+	case e.Cost < 0:
+		at.Message = fmt.Sprintf("cost %g is negative", e.Cost)
+		problems = append(problems, at)
+	}
+
+	return problems
+}
@@ -0,0 +1,115 @@
+package graphio_test
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/graphio"
+	"testing"
+)
+
+func TestValidateEdges(t *testing.T) {
+	edges := []graph.Edge{
+		{From: 0, To: 1, Cost: 1},
+		{From: -1, To: 3, Cost: 1},
+		{From: 1, To: 2, Cost: -2},
+		{From: 2, To: 0, Cost: math.NaN()},
+		{From: 2, To: 1, Cost: math.Inf(0)},
+	}
+
+	problems := graphio.ValidateEdges(edges, 3)
+
+	var got []int
+	for _, p := range problems {
+		got = append(got, p.Edge)
+	}
+
+	if expected := []int{1, 1, 2, 3, 4}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected problems for edges %v, got %v", expected, problems)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "graph.gr")
+	data := "c test\np sp 3 4\na 1 2 5\na 1 4 3\na 2 3 -1\n"
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := graphio.Validate(graphio.NewDIMANCSInput(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []graphio.Problem{
+		{Edge: -1, Message: "header declares 4 edges but data contains 3"},
+		{Edge: 1, File: file, Line: 4, Message: "target node 3 out of range [0, 3)"},
+		{Edge: 2, File: file, Line: 5, Message: "cost -1 is negative"},
+	}
+
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("expected %v, got %v", expected, problems)
+	}
+}
+
+func TestValidateLines(t *testing.T) {
+	mtx := writeTestFile(t, "graph.mtx", "%%MatrixMarket matrix coordinate real symmetric\n3 3 2\n2 1 1\n3 2 -1\n")
+	metis := writeTestFile(t, "graph.graph", "3 2 001\n2 1\n1 1 3 -1\n2 -1\n")
+
+	tests := []struct {
+		name     string
+		in       graphio.GraphInput
+		expected []graphio.Problem
+	}{
+		{
+			name: "symmetric mtx",
+			in:   graphio.NewMTXInput(mtx),
+			expected: []graphio.Problem{
+				{Edge: 2, File: mtx, Line: 4, Message: "cost -1 is negative"},
+				{Edge: 3, File: mtx, Line: 4, Message: "cost -1 is negative"},
+			},
+		},
+		{
+			name: "metis",
+			in:   graphio.NewMETISInput(metis),
+			expected: []graphio.Problem{
+				{Edge: 2, File: metis, Line: 3, Message: "cost -1 is negative"},
+				{Edge: 3, File: metis, Line: 4, Message: "cost -1 is negative"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		problems, err := graphio.Validate(tt.in)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(problems, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, problems)
+		}
+	}
+}
+
+func TestValidateHeaderCounts(t *testing.T) {
+	tests := map[string]graphio.GraphInput{
+		"negative n":       graphio.NewDIMANCSInput(writeTestFile(t, "graph.gr", "p sp -3 1\na 1 2 3\n")),
+		"negative m":       graphio.NewDIMANCSInput(writeTestFile(t, "graph.gr", "p sp 3 -1\na 1 2 3\n")),
+		"negative entries": graphio.NewMTXInput(writeTestFile(t, "graph.mtx", "%%MatrixMarket matrix coordinate pattern general\n3 3 -2\n1 2\n")),
+	}
+
+	for name, in := range tests {
+		var pe *graphio.ParseError
+		if _, err := graphio.Validate(in); !errors.As(err, &pe) {
+			t.Errorf("%s: expected a ParseError, got %v", name, err)
+		}
+	}
+
+	// Huge declared counts are not preallocated.
+	in := graphio.NewDIMANCSInput(writeTestFile(t, "graph.gr", "p sp 2 9000000000000000000\na 1 2 3\n"))
+	if _, err := graphio.Validate(in); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
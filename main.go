@@ -1,11 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"route-planning/benchmark"
 	"route-planning/graph"
 	"route-planning/graphio"
 	"route-planning/shortestpath"
+	"route-planning/stats"
 	"time"
 
 	"github.com/jessevdk/go-flags"
@@ -21,14 +25,99 @@ type DijkstraCommand struct {
 
 	Bidirectional bool `short:"b" long:"bidirect" description:"use bidirectional mode"`
 
+	GeoJSON     string `long:"geojson" description:"write the path as GeoJSON to this file"`
+	SearchSpace string `long:"search-space" description:"write the settled nodes as GeoJSON to this file"`
+	GPX         string `long:"gpx" description:"write the path as GPX to this file"`
+	GPXType     string `long:"gpx-type" description:"store the path as GPX track or route" choice:"track" choice:"route" default:"track"`
+	KML         string `long:"kml" description:"write the path as KML to this file"`
+	DOT         string `long:"dot" description:"write the graph with the path highlighted as Graphviz DOT to this file"`
+	GraphML     string `long:"graphml" description:"write the graph with the path highlighted as GraphML to this file"`
+
+	FileArg FileArg `positional-args:"true" required:"true"`
+}
+
+type StatsCommand struct {
+	JSON bool `long:"json" description:"print the report as JSON"`
+
+	FileArg FileArg `positional-args:"true" required:"true"`
+}
+
+type QueriesCommand struct {
+	Queries string `short:"q" long:"queries" required:"true" description:"DIMACS .ss or .p2p query file"`
+	Output  string `short:"o" long:"output" description:"result file, defaults to stdout"`
+
+	Bidirectional bool `short:"b" long:"bidirect" description:"use bidirectional mode for point-to-point queries"`
+
+	FileArg FileArg `positional-args:"true" required:"true"`
+}
+
+type ValidateCommand struct {
 	FileArg FileArg `positional-args:"true" required:"true"`
 }
 
 var cli struct {
 	Dijkstra DijkstraCommand `command:"dijkstra"`
+	Stats    StatsCommand    `command:"stats" description:"print statistics of the graph"`
+	Validate ValidateCommand `command:"validate" description:"check the graph for invalid edges"`
+	Queries  QueriesCommand  `command:"queries" description:"answer DIMACS challenge query files"`
+	Convert  ConvertCommand  `command:"convert" description:"write the graph or a subgraph in another format"`
+
+	Format      string `short:"f" long:"format" description:"the input format, detected from the content by default" choice:"auto" choice:"mtx" choice:"dimacs" choice:"metis" choice:"csv" choice:"tsv" choice:"snap" choice:"osm-pbf" choice:"osm-xml" default:"auto"`
+	Coordinates string `short:"c" long:"coordinates" description:"DIMACS .co file with the node coordinates"`
+	Verbose     bool   `short:"v" long:"verbose" description:"display additional information"`
+	Workers     int    `long:"workers" description:"goroutines parsing DIMACS and SNAP inputs, defaults to the number of CPUs"`
+	Lenient     bool   `long:"lenient" description:"skip malformed edge lines of DIMACS, MatrixMarket, SNAP and CSV inputs instead of aborting"`
+
+	Normalize    bool `long:"normalize" description:"remove self-loops and all but the cheapest of parallel edges"`
+	KeepParallel bool `long:"keep-parallel" description:"only report parallel edges when normalizing"`
+	Symmetrize   bool `long:"symmetrize" description:"add missing reverse edges when normalizing"`
 
-	Format  string `short:"f" long:"format" description:"the input format" choice:"mtx" choice:"dimacs" default:"dimacs"`
-	Verbose bool   `short:"v" long:"verbose" description:"display additional information"`
+	Cache       string `long:"cache" description:"binary graph cache, written on the first run and loaded instead of parsing the input afterwards"`
+	VerifyCache bool   `long:"verify-cache" description:"check the checksum of the whole cache when loading it"`
+
+	Profile string `long:"profile" description:"the routing profile for OpenStreetMap inputs" choice:"car" choice:"bike" choice:"foot" default:"car"`
+	Metric  string `long:"metric" description:"the edge cost for OpenStreetMap inputs" choice:"distance" choice:"time" default:"time"`
+
+	CSV  CSVFlags  `group:"CSV/TSV Options"`
+	SNAP SNAPFlags `group:"SNAP Edge List Options"`
+}
+
+type SNAPFlags struct {
+	Directed bool `long:"snap-directed" description:"every line is a directed edge"`
+	OneBased bool `long:"snap-one-based" description:"node IDs start at 1 as in networkrepository files"`
+	Compact  bool `long:"snap-compact" description:"number sparse node IDs densely"`
+}
+
+func (f SNAPFlags) options() graphio.SNAPOptions {
+	return graphio.SNAPOptions{
+		Directed: f.Directed,
+		OneBased: f.OneBased,
+		Compact:  f.Compact,
+	}
+}
+
+type CSVFlags struct {
+	Header    bool     `long:"csv-header" description:"the first row contains the column names"`
+	From      string   `long:"csv-from" description:"name or 0-based index of the source column" default:"0"`
+	To        string   `long:"csv-to" description:"name or 0-based index of the target column" default:"1"`
+	Cost      string   `long:"csv-cost" description:"name or 0-based index of the cost column, empty for unit costs" default:"2"`
+	Metrics   []string `long:"csv-metric" description:"additional metric column, may be repeated"`
+	OneBased  bool     `long:"csv-one-based" description:"numeric node IDs start at 1"`
+	StringIDs bool     `long:"csv-string-ids" description:"node IDs are arbitrary strings"`
+}
+
+// options returns the options of the CSV input delimited by comma.
+func (f CSVFlags) options(comma rune) graphio.CSVOptions {
+	return graphio.CSVOptions{
+		Comma:     comma,
+		Header:    f.Header,
+		From:      f.From,
+		To:        f.To,
+		Cost:      f.Cost,
+		Metrics:   f.Metrics,
+		OneBased:  f.OneBased,
+		StringIDs: f.StringIDs,
+	}
 }
 
 func main() {
@@ -41,35 +130,25 @@ func main() {
 		os.Exit(1)
 	}
 
-	var in graphio.GraphInput
-	switch cli.Format {
-	case "dimacs":
-		in = graphio.NewDIMANCSInput(cli.Dijkstra.FileArg.File)
-	case "mtx":
-		in = graphio.NewMTXInput(cli.Dijkstra.FileArg.File)
+	switch p.Active.Name {
+	case "dijkstra":
+		runDijkstra(cli.Dijkstra)
+	case "stats":
+		runStats(cli.Stats)
+	case "validate":
+		runValidate(cli.Validate)
+	case "queries":
+		runQueries(cli.Queries)
+	case "convert":
+		runConvert(cli.Convert)
 	}
+}
 
-	edges, n, err := in.LoadGraph()
-	if err != nil {
-		fmt.Printf("Error loading graph from input: %v\n", err)
-		os.Exit(1)
-	}
-
-	fmt.Printf("Loaded %d edges and %d nodes\n", len(edges), n)
-
-	g := graph.NewAdjacencyList(edges, n)
-
-	var algo shortestpath.Algorithm
-	algo = &shortestpath.Dijkstra{Graph: g}
-
-	if cli.Dijkstra.Bidirectional {
-		algo = &shortestpath.BidirectDijkstra{
-			ForwardGraph:  g,
-			BackwardGraph: g.Reverted(),
-		}
-	}
+func runDijkstra(cmd DijkstraCommand) {
+	g, in := loadGraph(cmd.FileArg.File, os.Stdout)
+	algo, _ := newAlgorithm(g, cmd.Bidirectional)
 
-	s, t := graph.Node(cli.Dijkstra.Source-1), graph.Node(cli.Dijkstra.Target-1)
+	s, t := graph.Node(cmd.Source-1), graph.Node(cmd.Target-1)
 
 	start := time.Now()
 	c, path := algo.Pair(s, t)
@@ -92,4 +171,516 @@ func main() {
 		fmt.Printf("%v\n", pathNodes)
 	}
 
+	if cmd.DOT != "" {
+		writeFile(cmd.DOT, func(w io.Writer) error {
+			return graphio.NewDOTOutput(path).WriteGraph(w, g)
+		})
+	}
+
+	if cmd.GraphML != "" {
+		writeFile(cmd.GraphML, func(w io.Writer) error {
+			return graphio.NewGraphMLOutput(path).WriteGraph(w, g)
+		})
+	}
+
+	if cmd.GeoJSON == "" && cmd.SearchSpace == "" && cmd.GPX == "" && cmd.KML == "" {
+		return
+	}
+
+	coords := loadCoordinates(in, g.N())
+	out := graphio.NewGeoJSONOutput(coords)
+
+	if cmd.GeoJSON != "" {
+		writeFile(cmd.GeoJSON, func(w io.Writer) error {
+			return out.WritePath(w, path)
+		})
+	}
+
+	if cmd.GPX != "" {
+		kind := graphio.GPXTrack
+		if cmd.GPXType == "route" {
+			kind = graphio.GPXRoute
+		}
+		writeFile(cmd.GPX, func(w io.Writer) error {
+			return graphio.NewGPXOutput(coords, kind).WritePath(w, path)
+		})
+	}
+
+	if cmd.KML != "" {
+		writeFile(cmd.KML, func(w io.Writer) error {
+			return graphio.NewKMLOutput(coords).WritePath(w, path)
+		})
+	}
+
+	if cmd.SearchSpace != "" {
+		settled, cost := shortestpath.SearchSpace(g, s, t)
+		fmt.Printf("Settled nodes: %d\n", len(settled))
+
+		writeFile(cmd.SearchSpace, func(w io.Writer) error {
+			return out.WriteNodes(w, settled, cost)
+		})
+	}
+}
+
+func runStats(cmd StatsCommand) {
+	// Keep stdout clean for the JSON report.
+	log := io.Writer(os.Stdout)
+	if cmd.JSON {
+		log = os.Stderr
+	}
+
+	g, _ := loadGraph(cmd.FileArg.File, log)
+
+	start := time.Now()
+	report := stats.Compute(g)
+	fmt.Fprintf(log, "Computing statistics took: %v\n", time.Since(start))
+
+	var err error
+	if cmd.JSON {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		fmt.Printf("Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runQueries(cmd QueriesCommand) {
+	queries, err := graphio.NewDIMACSQueryInput(cmd.Queries).LoadQueries()
+	if err != nil {
+		fmt.Printf("Error loading queries: %v\n", err)
+		os.Exit(1)
+	}
+
+	if cmd.Bidirectional && queries.Kind == graphio.SingleSource {
+		fmt.Println("--bidirect cannot be used with single-source queries")
+		os.Exit(1)
+	}
+
+	// Keep stdout clean if the results are written to it.
+	log := io.Writer(os.Stdout)
+	out := io.Writer(os.Stdout)
+	if cmd.Output == "" {
+		log = os.Stderr
+	} else {
+		f, err := os.Create(cmd.Output)
+		if err != nil {
+			fmt.Printf("Error creating output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	g, _ := loadGraph(cmd.FileArg.File, log)
+	algo, solver := newAlgorithm(g, cmd.Bidirectional)
+
+	for _, q := range queries.Queries {
+		if int(q.Source) >= g.N() || int(q.Target) >= g.N() || q.Source < 0 || q.Target < 0 {
+			fmt.Printf("Query %d -> %d references a node not in the graph\n", q.Source+1, q.Target+1)
+			os.Exit(1)
+		}
+	}
+
+	start := time.Now()
+	res, err := benchmark.Run(g, algo, queries)
+	if err != nil {
+		fmt.Printf("Error answering queries: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(log, "Answered %d %s queries in %v\n", len(queries.Queries), queries.Kind, time.Since(start))
+
+	res.Solver = solver
+	res.GraphFile = cmd.FileArg.File
+	res.QueryFile = cmd.Queries
+
+	if err := graphio.WriteDIMACSResults(out, res); err != nil {
+		fmt.Printf("Error writing results: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runValidate(cmd ValidateCommand) {
+	in := newInput(cmd.FileArg.File, os.Stdout)
+	problems, err := graphio.Validate(in)
+	exitOnLoadError(err)
+
+	reportSkippedLines(in, os.Stdout)
+
+	if len(problems) == 0 {
+		fmt.Println("No problems found")
+		return
+	}
+
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	fmt.Printf("Found %d problems\n", len(problems))
+	os.Exit(1)
+}
+
+// maxReportedProblems limits the problems printed when loading an invalid
+// graph.
+const maxReportedProblems = 10
+
+// loadGraph loads the graph from file according to the global options and
+// exits on failure. Progress is reported to log. The input is returned to
+// load additional data provided by it.
+func loadGraph(file string, log io.Writer) (graph.Graph, graphio.GraphInput) {
+	var meta graphio.CacheMetadata
+	if cli.Cache != "" && file != stdinFile {
+		var err error
+		meta, err = graphio.NewCacheMetadata(file, cacheOptions())
+		if err != nil {
+			fmt.Printf("Error loading graph from input: %v\n", err)
+			os.Exit(1)
+		}
+
+		if c := loadCache(meta, log); c != nil {
+			return c.Graph, c
+		}
+	}
+
+	g, in := parseGraph(file, log)
+
+	if cli.Cache != "" && file != stdinFile {
+		writeCache(g, in, meta, log)
+	}
+
+	return g, in
+}
+
+// parseGraph parses the graph from file according to the global options and
+// exits on failure.
+func parseGraph(file string, log io.Writer) (graph.Graph, graphio.GraphInput) {
+	in := newInput(file, log)
+
+	if ui, ok := in.(graphio.UndirectedGraphInput); ok {
+		edges, n, err := ui.LoadUndirectedGraph()
+		if !errors.Is(err, graphio.ErrDirected) {
+			exitOnLoadError(err)
+			reportSkippedLines(in, log)
+			return buildGraph(edges, n, true, log), in
+		}
+	}
+
+	// Without normalization the graph can be built without keeping a list
+	// of all edges.
+	if !cli.Normalize {
+		g, problems, err := graphio.LoadCSR(in)
+		exitOnLoadError(err)
+		reportSkippedLines(in, log)
+		exitOnProblems(problems)
+
+		fmt.Fprintf(log, "Loaded %d edges and %d nodes\n", len(g.Targets), g.N())
+		return g, in
+	}
+
+	edges, n, err := in.LoadGraph()
+	exitOnLoadError(err)
+	reportSkippedLines(in, log)
+	return buildGraph(edges, n, false, log), in
+}
+
+// reportSkippedLines prints the first malformed lines skipped by a lenient
+// input to log.
+func reportSkippedLines(in graphio.GraphInput, log io.Writer) {
+	li, ok := in.(graphio.LenientInput)
+	if !ok || len(li.SkippedLines()) == 0 {
+		return
+	}
+
+	skipped := li.SkippedLines()
+	fmt.Fprintf(log, "Skipped %d malformed lines:\n", len(skipped))
+	for i, err := range skipped {
+		if i == maxReportedProblems {
+			fmt.Fprintln(log, "...")
+			break
+		}
+		fmt.Fprintf(log, "\t%v\n", err)
+	}
+}
+
+func exitOnLoadError(err error) {
+	if err != nil {
+		fmt.Printf("Error loading graph from input: %v\n", err)
+		if errors.Is(err, graphio.ErrSparseNodeIDs) {
+			fmt.Println("Use --snap-compact or --csv-string-ids to number the node IDs densely")
+		}
+		os.Exit(1)
+	}
+}
+
+// exitOnProblems prints the first problems and exits if there are any.
+func exitOnProblems(problems []graphio.Problem) {
+	if len(problems) == 0 {
+		return
+	}
+
+	fmt.Printf("Graph contains %d invalid edges:\n", len(problems))
+	for i, p := range problems {
+		if i == maxReportedProblems {
+			fmt.Println("...")
+			break
+		}
+		fmt.Printf("\t%v\n", p)
+	}
+	os.Exit(1)
+}
+
+// buildGraph validates and normalizes the edges and builds the graph.
+func buildGraph(edges []graph.Edge, n int, undirected bool, log io.Writer) graph.Graph {
+	exitOnProblems(graphio.ValidateEdges(edges, n))
+
+	fmt.Fprintf(log, "Loaded %d edges and %d nodes\n", len(edges), n)
+
+	if cli.Normalize {
+		if undirected && cli.Symmetrize {
+			fmt.Println("--symmetrize cannot be used with undirected inputs, their edges are symmetric already")
+			os.Exit(1)
+		}
+
+		var report graph.NormalizeReport
+		edges, report = graph.Normalize(edges, graph.NormalizeOptions{
+			KeepParallel: cli.KeepParallel,
+			Symmetrize:   cli.Symmetrize,
+			Undirected:   undirected,
+		})
+
+		fmt.Fprintf(log, "Normalized to %d edges (self-loops: %d, parallel edges: %d, added reverse edges: %d)\n",
+			len(edges), report.SelfLoops, report.ParallelEdges, report.AddedReverse)
+	}
+
+	if undirected {
+		g, err := graph.NewUndirected(edges, n)
+		if err != nil {
+			fmt.Printf("Error building the graph: %v\n", err)
+			os.Exit(1)
+		}
+		return g
+	}
+	return graph.NewAdjacencyList(edges, n)
+}
+
+// cacheOptions describes the global options affecting the loaded graph.
+func cacheOptions() string {
+	return fmt.Sprintf("format=%s lenient=%t normalize=%t keep-parallel=%t symmetrize=%t profile=%s metric=%s coordinates=%s csv=%+v snap=%+v",
+		cli.Format, cli.Lenient, cli.Normalize, cli.KeepParallel, cli.Symmetrize, cli.Profile, cli.Metric, cli.Coordinates, cli.CSV, cli.SNAP)
+}
+
+// loadCache returns the cached graph if the cache was built from the input
+// described by meta and nil otherwise.
+func loadCache(meta graphio.CacheMetadata, log io.Writer) *graphio.Cache {
+	if _, err := os.Stat(cli.Cache); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	start := time.Now()
+	c, err := graphio.LoadCache(cli.Cache, cli.VerifyCache)
+	if err != nil {
+		fmt.Fprintf(log, "Ignoring cache: %v\n", err)
+		return nil
+	}
+
+	if c.Metadata != meta {
+		fmt.Fprintf(log, "Ignoring cache %s built from a different input or with different options\n", cli.Cache)
+		c.Close()
+		return nil
+	}
+
+	fmt.Fprintf(log, "Loaded %d edges and %d nodes from cache in %v\n", len(c.Graph.Targets), c.Graph.N(), time.Since(start))
+	return c
+}
+
+// writeCache writes g and the coordinates available for it to the cache.
+// Failing to write the cache is not fatal.
+func writeCache(g graph.Graph, in graphio.GraphInput, meta graphio.CacheMetadata, log io.Writer) {
+	csr, ok := g.(*graph.CSR)
+	if !ok {
+		var edges []graph.Edge
+		for v := 0; v < g.N(); v++ {
+			edges = append(edges, g.OutgoingEdges(graph.Node(v))...)
+		}
+		var err error
+		if csr, err = graph.NewCSR(edges, g.N()); err != nil {
+			fmt.Fprintf(log, "Error writing cache: %v\n", err)
+			return
+		}
+	}
+
+	var coords []graph.Coordinate
+	if _, ok := in.(graphio.CoordinateInput); ok || cli.Coordinates != "" {
+		var err error
+		if coords, err = readCoordinates(in, g.N()); err != nil {
+			fmt.Fprintf(log, "Error writing cache: %v\n", err)
+			return
+		}
+	}
+
+	if err := graphio.WriteCacheFile(cli.Cache, csr, coords, meta); err != nil {
+		fmt.Fprintf(log, "Error writing cache: %v\n", err)
+		return
+	}
+
+	fmt.Fprintf(log, "Wrote cache %s\n", cli.Cache)
+}
+
+// loadCoordinates returns the coordinates of the n nodes of the graph loaded
+// from in and exits on failure, see readCoordinates.
+func loadCoordinates(in graphio.GraphInput, n int) []graph.Coordinate {
+	coords, err := readCoordinates(in, n)
+	if err != nil {
+		fmt.Printf("Error loading coordinates: %v\n", err)
+		os.Exit(1)
+	}
+
+	return coords
+}
+
+// readCoordinates returns the coordinates of the n nodes of the graph loaded
+// from in. They are taken from the input itself if it provides them and from
+// the coordinates file otherwise.
+func readCoordinates(in graphio.GraphInput, n int) ([]graph.Coordinate, error) {
+	ci, ok := in.(graphio.CoordinateInput)
+	if !ok {
+		if cli.Coordinates == "" {
+			return nil, errors.New("the input format provides no coordinates, use --coordinates")
+		}
+		ci = graphio.NewDIMACSCoordinateInput(cli.Coordinates)
+	}
+
+	coords, err := ci.LoadCoordinates()
+	if err != nil {
+		return nil, err
+	}
+
+	return coords, graphio.MatchCoordinates(coords, n)
+}
+
+// writeFile creates file, writes to it using write and exits on failure.
+func writeFile(file string, write func(io.Writer) error) {
+	f, err := os.Create(file)
+	if err != nil {
+		fmt.Printf("Error creating output file: %v\n", err)
+		os.Exit(1)
+	}
+
+	err = write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Printf("Error writing %s: %v\n", file, err)
+		os.Exit(1)
+	}
+}
+
+// newAlgorithm returns the shortest path algorithm to use on g and its name.
+func newAlgorithm(g graph.Graph, bidirectional bool) (shortestpath.Algorithm, string) {
+	if bidirectional {
+		return &shortestpath.BidirectDijkstra{
+			ForwardGraph:  g,
+			BackwardGraph: g.Reverted(),
+		}, "bidirect-dijkstra"
+	}
+
+	return &shortestpath.Dijkstra{Graph: g}, "dijkstra"
+}
+
+// stdinFile is the file name selecting standard input.
+const stdinFile = "-"
+
+// autoFormat is the format selecting detection from the input.
+const autoFormat = "auto"
+
+func exitOnDetectError(err error) {
+	if err != nil {
+		fmt.Printf("Error detecting the input format: %v\nSelect the format with --format\n", err)
+		os.Exit(1)
+	}
+}
+
+// newInput returns the input reading file in the selected format. A detected
+// format is reported to log.
+func newInput(file string, log io.Writer) graphio.GraphInput {
+	if file == stdinFile {
+		return newStdinInput(log)
+	}
+
+	format := graphio.Format(cli.Format)
+	if format == autoFormat {
+		var err error
+		format, err = graphio.DetectFormat(file)
+		exitOnDetectError(err)
+		fmt.Fprintf(log, "Detected format %s\n", format)
+	}
+
+	switch format {
+	case "mtx":
+		return graphio.NewMTXInput(file, parseOptions()...)
+	case "metis":
+		return graphio.NewMETISInput(file)
+	case "csv":
+		return graphio.NewCSVInput(file, cli.CSV.options(','), parseOptions()...)
+	case "tsv":
+		return graphio.NewCSVInput(file, cli.CSV.options('\t'), parseOptions()...)
+	case "snap":
+		return graphio.NewSNAPInput(file, cli.SNAP.options(), parseOptions()...)
+	case "osm-pbf":
+		return graphio.NewOSMPBFInput(file, graphio.OSMProfiles[cli.Profile], osmMetric())
+	case "osm-xml":
+		return graphio.NewOSMXMLInput(file, graphio.OSMProfiles[cli.Profile], osmMetric())
+	default:
+		return graphio.NewDIMANCSInput(file, parseOptions()...)
+	}
+}
+
+// newStdinInput returns the input reading the graph from stdin. OpenStreetMap
+// data is read in two passes and therefore requires a file.
+func newStdinInput(log io.Writer) graphio.GraphInput {
+	format, r := graphio.Format(cli.Format), io.Reader(os.Stdin)
+	if format == autoFormat {
+		var err error
+		format, r, err = graphio.DetectFormatFromReader(os.Stdin)
+		exitOnDetectError(err)
+		fmt.Fprintf(log, "Detected format %s\n", format)
+	}
+
+	switch format {
+	case "mtx":
+		return graphio.NewMTXInputFromReader(r, parseOptions()...)
+	case "dimacs":
+		return graphio.NewDIMACSInputFromReader(r, parseOptions()...)
+	case "metis":
+		return graphio.NewMETISInputFromReader(r)
+	case "csv":
+		return graphio.NewCSVInputFromReader(r, cli.CSV.options(','), parseOptions()...)
+	case "tsv":
+		return graphio.NewCSVInputFromReader(r, cli.CSV.options('\t'), parseOptions()...)
+	case "snap":
+		return graphio.NewSNAPInputFromReader(r, cli.SNAP.options(), parseOptions()...)
+	default:
+		fmt.Printf("Format %s cannot be read from stdin\n", format)
+		os.Exit(1)
+		return nil
+	}
+}
+
+// parseOptions returns the options of the text parsers selected by the global
+// flags.
+func parseOptions() []graphio.ParseOption {
+	opts := []graphio.ParseOption{graphio.WithWorkers(cli.Workers)}
+	if cli.Lenient {
+		opts = append(opts, graphio.WithLenient())
+	}
+	return opts
+}
+
+func osmMetric() graphio.OSMMetric {
+	if cli.Metric == "distance" {
+		return graphio.Distance
+	}
+	return graphio.TravelTime
 }
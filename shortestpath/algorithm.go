@@ -5,3 +5,9 @@ import "route-planning/graph"
 type Algorithm interface {
 	Pair(s, t graph.Node) (float64, []graph.Edge)
 }
+
+// OneToAll is implemented by algorithms which compute the distances from a
+// source to all nodes in a single run.
+type OneToAll interface {
+	ToAll(s graph.Node) ([]float64, []graph.Edge)
+}
@@ -102,7 +102,9 @@ func (bd *BidirectDijkstra) Pair(s, t graph.Node) (float64, []graph.Edge) {
 
 	upperBound := math.Inf(0)
 	var meetingNode graph.Node
-	for forward.pq.Len() > 0 || backward.pq.Len() > 0 {
+	// Once one direction has settled all of its reachable nodes, every
+	// connecting path has been seen and the upper bound is exact.
+	for forward.pq.Len() > 0 && backward.pq.Len() > 0 {
 
 		if upperBound < backward.pq.Top().Cost+forward.pq.Top().Cost {
 			break
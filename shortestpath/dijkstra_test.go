@@ -1,6 +1,7 @@
 package shortestpath_test
 
 import (
+	"math"
 	"math/rand"
 	"reflect"
 	"route-planning/graph"
@@ -104,6 +105,24 @@ func TestToAll(t *testing.T) {
 	}
 }
 
+func TestBidirectPairDisconnected(t *testing.T) {
+	g := graph.NewAdjacencyList([]graph.Edge{
+		{From: 0, To: 1, Cost: 1},
+		{From: 1, To: 0, Cost: 1},
+		{From: 2, To: 3, Cost: 1},
+		{From: 3, To: 2, Cost: 1},
+		{From: 3, To: 4, Cost: 1},
+	}, 5)
+	sut := shortestpath.BidirectDijkstra{ForwardGraph: g, BackwardGraph: g.Reverted()}
+
+	for _, pair := range [][2]graph.Node{{0, 3}, {3, 0}, {1, 4}, {4, 3}} {
+		cost, path := sut.Pair(pair[0], pair[1])
+		if !math.IsInf(cost, 1) || len(path) != 0 {
+			t.Errorf("sp(%d, %d): expected no path, got cost %f and %v", pair[0], pair[1], cost, path)
+		}
+	}
+}
+
 func BenchmarkPair(b *testing.B) {
 	b.StopTimer()
 	n := 100_000
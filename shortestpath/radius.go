@@ -0,0 +1,30 @@
+package shortestpath
+
+import (
+	"fmt"
+	"route-planning/graph"
+	"route-planning/priorityqueue"
+)
+
+// RadiusSubgraph returns the subgraph induced by all nodes with a network
+// distance of at most radius from center.
+func RadiusSubgraph(g graph.Graph, center graph.Node, radius float64) (graph.Subgraph, error) {
+	if center < 0 || int(center) >= g.N() {
+		return graph.Subgraph{}, fmt.Errorf("center %d out of range [0, %d)", center, g.N())
+	}
+
+	stop := func(element priorityqueue.Element, state DijkstraState) bool {
+		return element.Cost > radius
+	}
+
+	cost, _ := Dijkstra{Graph: g}.Run(center, stop)
+
+	nodes := []graph.Node{center}
+	for i, c := range cost {
+		if v := graph.Node(i); v != center && c <= radius {
+			nodes = append(nodes, v)
+		}
+	}
+
+	return graph.InducedSubgraph(g, nodes)
+}
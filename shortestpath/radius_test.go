@@ -0,0 +1,39 @@
+package shortestpath_test
+
+import (
+	"route-planning/graph"
+	"route-planning/shortestpath"
+	"testing"
+)
+
+func TestRadiusSubgraph(t *testing.T) {
+	radius := 8.0
+
+	for v := 0; v < testGraph.N(); v++ {
+		sut, err := shortestpath.RadiusSubgraph(testGraph, graph.Node(v), radius)
+		if err != nil {
+			t.Fatalf("radius(%d): unexpected error: %v", v, err)
+		}
+
+		if sut.Original[0] != graph.Node(v) {
+			t.Errorf("radius(%d): expected center to be node 0, got %d", v, sut.Original[0])
+		}
+
+		expected := 0
+		for _, c := range expectedCosts[v] {
+			if c <= radius {
+				expected++
+			}
+		}
+
+		if sut.Graph.N() != expected {
+			t.Errorf("radius(%d): expected %d nodes, got %d", v, expected, sut.Graph.N())
+		}
+
+		for _, w := range sut.Original {
+			if c := expectedCosts[v][w]; c > radius {
+				t.Errorf("radius(%d): node %d has distance %f", v, w, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package shortestpath
+
+import (
+	"route-planning/graph"
+	"route-planning/priorityqueue"
+)
+
+// SearchSpace returns the nodes settled by Dijkstra's algorithm on g when
+// searching from s to t in the order they are settled together with the
+// tentative costs of all nodes.
+func SearchSpace(g graph.Graph, s, t graph.Node) ([]graph.Node, []float64) {
+	var settled []graph.Node
+
+	stop := func(element priorityqueue.Element, state DijkstraState) bool {
+		if element.Cost <= state.Cost[element.Node] {
+			settled = append(settled, element.Node)
+		}
+		return element.Node == t
+	}
+
+	cost, _ := Dijkstra{Graph: g}.Run(s, stop)
+
+	return settled, cost
+}
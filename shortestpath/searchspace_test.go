@@ -0,0 +1,23 @@
+package shortestpath_test
+
+import (
+	"route-planning/shortestpath"
+	"testing"
+)
+
+func TestSearchSpace(t *testing.T) {
+	settled, cost := shortestpath.SearchSpace(testGraph, 0, 6)
+
+	if settled[0] != 0 || settled[len(settled)-1] != 6 {
+		t.Errorf("expected search space to start at 0 and end at 6, got %v", settled)
+	}
+
+	for i, v := range settled {
+		if cost[v] != expectedCosts[0][v] {
+			t.Errorf("settled node %d: expected cost %f, got %f", v, expectedCosts[0][v], cost[v])
+		}
+		if i > 0 && cost[v] < cost[settled[i-1]] {
+			t.Errorf("nodes not settled in order of cost: %v", settled)
+		}
+	}
+}
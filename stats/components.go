@@ -0,0 +1,126 @@
+package stats
+
+import "route-planning/graph"
+
+// StronglyConnectedComponents returns the component of every node and the
+// number of strongly connected components in g.
+func StronglyConnectedComponents(g graph.Graph) ([]int, int) {
+	n := g.N()
+
+	const unvisited = -1
+
+	index := make([]int, n)
+	lowlink := make([]int, n)
+	onStack := make([]bool, n)
+	component := make([]int, n)
+	for i := range index {
+		index[i] = unvisited
+	}
+
+	type frame struct {
+		v     graph.Node
+		edges []graph.Edge
+		next  int
+	}
+
+	var stack []graph.Node
+	count, nextIndex := 0, 0
+
+	// Tarjan's algorithm with an explicit call stack as road networks easily
+	// exceed the goroutine stack with recursion.
+	for root := 0; root < n; root++ {
+		if index[root] != unvisited {
+			continue
+		}
+
+		calls := []frame{{v: graph.Node(root), edges: g.OutgoingEdges(graph.Node(root))}}
+		index[root], lowlink[root] = nextIndex, nextIndex
+		nextIndex++
+		stack = append(stack, graph.Node(root))
+		onStack[root] = true
+
+		for len(calls) > 0 {
+			f := &calls[len(calls)-1]
+			v := f.v
+
+			if f.next < len(f.edges) {
+				w := f.edges[f.next].To
+				f.next++
+
+				if index[w] == unvisited {
+					index[w], lowlink[w] = nextIndex, nextIndex
+					nextIndex++
+					stack = append(stack, w)
+					onStack[w] = true
+					calls = append(calls, frame{v: w, edges: g.OutgoingEdges(w)})
+				} else if onStack[w] && index[w] < lowlink[v] {
+					lowlink[v] = index[w]
+				}
+				continue
+			}
+
+			if lowlink[v] == index[v] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					component[w] = count
+					if w == v {
+						break
+					}
+				}
+				count++
+			}
+
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				if u := calls[len(calls)-1].v; lowlink[v] < lowlink[u] {
+					lowlink[u] = lowlink[v]
+				}
+			}
+		}
+	}
+
+	return component, count
+}
+
+// WeaklyConnectedComponents returns the component of every node and the
+// number of weakly connected components in g.
+func WeaklyConnectedComponents(g graph.Graph) ([]int, int) {
+	n := g.N()
+
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+
+	find := func(v int) int {
+		for parent[v] != v {
+			parent[v] = parent[parent[v]]
+			v = parent[v]
+		}
+		return v
+	}
+
+	for i := 0; i < n; i++ {
+		for _, e := range g.OutgoingEdges(graph.Node(i)) {
+			if a, b := find(int(e.From)), find(int(e.To)); a != b {
+				parent[a] = b
+			}
+		}
+	}
+
+	component := make([]int, n)
+	ids := make(map[int]int)
+	for i := 0; i < n; i++ {
+		root := find(i)
+		id, ok := ids[root]
+		if !ok {
+			id = len(ids)
+			ids[root] = id
+		}
+		component[i] = id
+	}
+
+	return component, len(ids)
+}
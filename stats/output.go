@@ -0,0 +1,42 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Nodes:\t%d\n", r.Nodes)
+	fmt.Fprintf(tw, "Edges:\t%d\n", r.Edges)
+	fmt.Fprintf(tw, "Isolated nodes:\t%d\n", r.IsolatedNodes)
+	fmt.Fprintf(tw, "Self-loops:\t%d\n", r.SelfLoops)
+	fmt.Fprintf(tw, "Degree (avg / max):\t%.2f / %d\n", r.AverageDegree, r.MaxDegree)
+	fmt.Fprintf(tw, "Weights (min / median / mean / max):\t%g / %g / %g / %g\n",
+		r.Weights.Min, r.Weights.Median, r.Weights.Mean, r.Weights.Max)
+	fmt.Fprintf(tw, "Weights (p90 / p99):\t%g / %g\n", r.Weights.P90, r.Weights.P99)
+	fmt.Fprintf(tw, "SCCs (largest):\t%d (%d)\n", r.StronglyConnectedComponents, r.LargestSCC)
+	fmt.Fprintf(tw, "WCCs (largest):\t%d (%d)\n", r.WeaklyConnectedComponents, r.LargestWCC)
+	fmt.Fprintf(tw, "Estimated diameter:\t%g (%d hops)\n", r.EstimatedDiameter, r.EstimatedDiameterHops)
+	if r.MemoryFootprint > 0 {
+		fmt.Fprintf(tw, "Memory footprint:\t%.1f MiB\n", float64(r.MemoryFootprint)/(1<<20))
+	}
+
+	fmt.Fprintf(tw, "Degree histogram:\t\n")
+	for d, count := range r.DegreeHistogram {
+		if count > 0 {
+			fmt.Fprintf(tw, "  %d\t%d\n", d, count)
+		}
+	}
+
+	return tw.Flush()
+}
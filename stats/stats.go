@@ -0,0 +1,278 @@
+package stats
+
+import (
+	"math"
+	"route-planning/graph"
+	"route-planning/shortestpath"
+	"sort"
+)
+
+type Report struct {
+	Nodes         int `json:"nodes"`
+	Edges         int `json:"edges"`
+	IsolatedNodes int `json:"isolated_nodes"`
+	SelfLoops     int `json:"self_loops"`
+
+	// DegreeHistogram[d] is the number of nodes with out-degree d.
+	DegreeHistogram []int   `json:"degree_histogram"`
+	MaxDegree       int     `json:"max_degree"`
+	AverageDegree   float64 `json:"average_degree"`
+
+	Weights WeightDistribution `json:"weights"`
+
+	StronglyConnectedComponents int `json:"strongly_connected_components"`
+	LargestSCC                  int `json:"largest_scc"`
+	WeaklyConnectedComponents   int `json:"weakly_connected_components"`
+	LargestWCC                  int `json:"largest_wcc"`
+
+	// EstimatedDiameter is a lower bound of the diameter found by a double
+	// sweep starting in the largest strongly connected component.
+	EstimatedDiameter     float64 `json:"estimated_diameter"`
+	EstimatedDiameterHops int     `json:"estimated_diameter_hops"`
+
+	// MemoryFootprint is the size of the graph in bytes or 0 if the graph
+	// does not report it.
+	MemoryFootprint int `json:"memory_footprint"`
+}
+
+// WeightDistribution describes the edge costs. Min, Max and Mean are exact,
+// the quantiles are estimated with a relative error below one percent.
+type WeightDistribution struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
+	P99    float64 `json:"p99"`
+}
+
+// Compute analyses g. Computing the report requires a few passes over all
+// edges and two runs of Dijkstra's algorithm.
+func Compute(g graph.Graph) Report {
+	n := g.N()
+	r := Report{Nodes: n}
+
+	// degree is the sum of in- and out-degree of every node.
+	degree := make([]int, n)
+	weights := newWeightHistogram()
+
+	for i := 0; i < n; i++ {
+		edges := g.OutgoingEdges(graph.Node(i))
+
+		r.Edges += len(edges)
+		for len(r.DegreeHistogram) <= len(edges) {
+			r.DegreeHistogram = append(r.DegreeHistogram, 0)
+		}
+		r.DegreeHistogram[len(edges)]++
+		degree[i] += len(edges)
+
+		for _, e := range edges {
+			degree[e.To]++
+			weights.add(e.Cost)
+			if e.From == e.To {
+				r.SelfLoops++
+			}
+		}
+	}
+
+	for _, d := range degree {
+		if d == 0 {
+			r.IsolatedNodes++
+		}
+	}
+
+	if len(r.DegreeHistogram) > 0 {
+		r.MaxDegree = len(r.DegreeHistogram) - 1
+	}
+	if n > 0 {
+		r.AverageDegree = float64(r.Edges) / float64(n)
+	}
+
+	r.Weights = weights.distribution()
+
+	scc, count := StronglyConnectedComponents(g)
+	r.StronglyConnectedComponents = count
+	largest, size := largestComponent(scc, count)
+	r.LargestSCC = size
+
+	wcc, count := WeaklyConnectedComponents(g)
+	r.WeaklyConnectedComponents = count
+	_, r.LargestWCC = largestComponent(wcc, count)
+
+	if n > 0 {
+		start := graph.Node(0)
+		for i, c := range scc {
+			if c == largest {
+				start = graph.Node(i)
+				break
+			}
+		}
+		inComponent := func(v graph.Node) bool {
+			return scc[v] == largest
+		}
+		r.EstimatedDiameter, r.EstimatedDiameterHops = doubleSweep(g, start, inComponent)
+	}
+
+	if sizer, ok := g.(graph.MemorySizer); ok {
+		r.MemoryFootprint = sizer.MemoryFootprint()
+	}
+
+	return r
+}
+
+// weightPrecision is the relative width of the buckets of weightHistogram.
+const weightPrecision = 0.01
+
+// weightBucketOffset shifts the bucket exponents so that the keys of all
+// positive weights are positive.
+const weightBucketOffset = 1 << 20
+
+// weightHistogram accumulates the weight distribution without keeping the
+// weights. Weights are counted in buckets growing by weightPrecision, which
+// bounds the number of buckets by the range of magnitudes rather than the
+// number of edges.
+type weightHistogram struct {
+	count    int
+	sum      float64
+	min, max float64
+	// buckets counts the weights by bucket, negative keys hold negative
+	// weights, key 0 holds zero weights.
+	buckets map[int]int
+}
+
+func newWeightHistogram() *weightHistogram {
+	return &weightHistogram{
+		min:     math.Inf(1),
+		max:     math.Inf(-1),
+		buckets: make(map[int]int),
+	}
+}
+
+func (h *weightHistogram) add(w float64) {
+	h.count++
+	h.sum += w
+	h.min = math.Min(h.min, w)
+	h.max = math.Max(h.max, w)
+	h.buckets[weightBucket(w)]++
+}
+
+// weightBucket returns the key of the bucket of w. Keys are ordered like the
+// weights of their buckets.
+func weightBucket(w float64) int {
+	if w == 0 || math.IsNaN(w) {
+		return 0
+	}
+
+	key := weightBucketOffset
+	if !math.IsInf(w, 0) {
+		key += int(math.Floor(math.Log(math.Abs(w)) / math.Log1p(weightPrecision)))
+	} else {
+		key *= 2
+	}
+
+	if w < 0 {
+		return -key
+	}
+	return key
+}
+
+func (h *weightHistogram) distribution() WeightDistribution {
+	if h.count == 0 {
+		return WeightDistribution{}
+	}
+
+	keys := make([]int, 0, len(h.buckets))
+	for key := range h.buckets {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	// quantile returns a weight of the bucket containing the weight with
+	// rank q * (count - 1).
+	quantile := func(q float64) float64 {
+		rank := int(q * float64(h.count-1))
+		for _, key := range keys {
+			rank -= h.buckets[key]
+			if rank < 0 {
+				return h.bucketWeight(key)
+			}
+		}
+		return h.max
+	}
+
+	return WeightDistribution{
+		Min:    h.min,
+		Max:    h.max,
+		Mean:   h.sum / float64(h.count),
+		Median: quantile(0.5),
+		P90:    quantile(0.9),
+		P99:    quantile(0.99),
+	}
+}
+
+// bucketWeight returns the geometric center of the bucket with key limited
+// to the range of the weights seen.
+func (h *weightHistogram) bucketWeight(key int) float64 {
+	if key == 0 {
+		return 0
+	}
+
+	exponent := key - weightBucketOffset
+	if key < 0 {
+		exponent = -key - weightBucketOffset
+	}
+
+	w := math.Pow(1+weightPrecision, float64(exponent)+0.5)
+	if key < 0 {
+		w = -w
+	}
+	return math.Max(h.min, math.Min(h.max, w))
+}
+
+func largestComponent(components []int, count int) (int, int) {
+	sizes := make([]int, count)
+	for _, c := range components {
+		sizes[c]++
+	}
+
+	largest, size := 0, 0
+	for c, s := range sizes {
+		if s > size {
+			largest, size = c, s
+		}
+	}
+
+	return largest, size
+}
+
+// doubleSweep runs Dijkstra's algorithm from start and again from the
+// farthest node found within the component of start. It returns the largest
+// distance of the second run and its number of hops.
+func doubleSweep(g graph.Graph, start graph.Node, inComponent func(graph.Node) bool) (float64, int) {
+	d := shortestpath.Dijkstra{Graph: g}
+
+	cost, _ := d.ToAll(start)
+	farthest, _ := farthestNode(cost, start, inComponent)
+
+	cost, predecessor := d.ToAll(farthest)
+	target, diameter := farthestNode(cost, farthest, func(graph.Node) bool { return true })
+
+	hops := 0
+	for v := target; v != farthest; v = predecessor[v].From {
+		hops++
+	}
+
+	return diameter, hops
+}
+
+func farthestNode(cost []float64, source graph.Node, consider func(graph.Node) bool) (graph.Node, float64) {
+	farthest := source
+	max := 0.0
+	for i, c := range cost {
+		if !math.IsInf(c, 0) && c > max && consider(graph.Node(i)) {
+			farthest, max = graph.Node(i), c
+		}
+	}
+
+	return farthest, max
+}
@@ -0,0 +1,62 @@
+package stats_test
+
+import (
+	"math"
+	"reflect"
+	"route-planning/graph"
+	"route-planning/stats"
+	"testing"
+)
+
+var testEdges = []graph.Edge{
+	{From: 0, To: 1, Cost: 1},
+	{From: 1, To: 2, Cost: 2},
+	{From: 2, To: 0, Cost: 3},
+	{From: 2, To: 3, Cost: 4},
+	{From: 4, To: 5, Cost: 5},
+	{From: 5, To: 5, Cost: 6},
+}
+
+func TestCompute(t *testing.T) {
+	g := graph.NewAdjacencyList(testEdges, 7)
+
+	sut := stats.Compute(g)
+
+	expectInt := func(name string, expected, got int) {
+		if expected != got {
+			t.Errorf("%s: expected %d, got %d", name, expected, got)
+		}
+	}
+
+	expectInt("nodes", 7, sut.Nodes)
+	expectInt("edges", 6, sut.Edges)
+	expectInt("isolated nodes", 1, sut.IsolatedNodes)
+	expectInt("self-loops", 1, sut.SelfLoops)
+	expectInt("max degree", 2, sut.MaxDegree)
+	expectInt("SCCs", 5, sut.StronglyConnectedComponents)
+	expectInt("largest SCC", 3, sut.LargestSCC)
+	expectInt("WCCs", 3, sut.WeaklyConnectedComponents)
+	expectInt("largest WCC", 4, sut.LargestWCC)
+	expectInt("diameter hops", 2, sut.EstimatedDiameterHops)
+
+	if sut.EstimatedDiameter != 4 {
+		t.Errorf("diameter: expected 4, got %f", sut.EstimatedDiameter)
+	}
+
+	if sut.Weights.Min != 1 || sut.Weights.Max != 6 || sut.Weights.Mean != 3.5 {
+		t.Errorf("unexpected weight distribution %+v", sut.Weights)
+	}
+
+	// The quantiles are estimated with a relative error below one percent.
+	if math.Abs(sut.Weights.Median-3) > 0.03 || math.Abs(sut.Weights.P90-5) > 0.05 {
+		t.Errorf("unexpected weight quantiles %+v", sut.Weights)
+	}
+
+	if expected := []int{2, 4, 1}; !reflect.DeepEqual(sut.DegreeHistogram, expected) {
+		t.Errorf("degree histogram: expected %v, got %v", expected, sut.DegreeHistogram)
+	}
+
+	if sut.MemoryFootprint == 0 {
+		t.Errorf("expected memory footprint to be reported")
+	}
+}
@@ -0,0 +1,448 @@
+package transit
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadGTFS reads a GTFS feed from a zip file or a directory.
+func LoadGTFS(path string) (*Timetable, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening GTFS feed %s: %w", path, err)
+	}
+
+	if info.IsDir() {
+		return LoadGTFSFS(os.DirFS(path))
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening GTFS feed %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	return LoadGTFSFS(zr)
+}
+
+// LoadGTFSFS reads a GTFS feed whose files are located in the root of fsys.
+// Either calendar.txt or calendar_dates.txt must be present, transfers.txt is
+// optional.
+func LoadGTFSFS(fsys fs.FS) (*Timetable, error) {
+	tt := &Timetable{
+		Stops:         make(map[string]*Stop),
+		Routes:        make(map[string]*Route),
+		Trips:         make(map[string]*Trip),
+		Calendars:     make(map[string]Calendar),
+		CalendarDates: make(map[string][]CalendarDate),
+	}
+
+	files := []struct {
+		name     string
+		required bool
+		parse    func(*Timetable, gtfsRecord) error
+	}{
+		{"stops.txt", true, parseStop},
+		{"routes.txt", true, parseRoute},
+		{"trips.txt", true, parseTrip},
+		{"stop_times.txt", true, parseStopTime},
+		{"calendar.txt", false, parseCalendar},
+		{"calendar_dates.txt", false, parseCalendarDate},
+		{"transfers.txt", false, parseTransfer},
+	}
+
+	for _, file := range files {
+		err := readGTFSFile(fsys, file.name, func(r gtfsRecord) error {
+			return file.parse(tt, r)
+		})
+		if errors.Is(err, fs.ErrNotExist) && !file.required {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("GTFS parsing failed: \n\t%w", err)
+		}
+	}
+
+	if len(tt.Calendars) == 0 && len(tt.CalendarDates) == 0 {
+		return nil, errors.New("GTFS parsing failed: \n\tfeed contains neither calendar.txt nor calendar_dates.txt")
+	}
+
+	for _, trip := range tt.Trips {
+		sort.SliceStable(trip.StopTimes, func(i, j int) bool {
+			return trip.StopTimes[i].Sequence < trip.StopTimes[j].Sequence
+		})
+	}
+
+	return tt, nil
+}
+
+// gtfsRecord is a line of a GTFS file with access to fields by column name.
+type gtfsRecord struct {
+	file    string
+	line    int
+	columns map[string]int
+	fields  []string
+}
+
+func (r gtfsRecord) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+func (r gtfsRecord) required(column string) (string, error) {
+	v := r.get(column)
+	if v == "" {
+		return "", r.errorf("missing required field %s", column)
+	}
+	return v, nil
+}
+
+// int parses an optional integer field, empty fields are 0.
+func (r gtfsRecord) int(column string) (int, error) {
+	if r.get(column) == "" {
+		return 0, nil
+	}
+	return r.requiredInt(column)
+}
+
+func (r gtfsRecord) requiredInt(column string) (int, error) {
+	v, err := r.required(column)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, r.errorf("error parsing %s: %v", column, err)
+	}
+	return i, nil
+}
+
+func (r gtfsRecord) float(column string) (float64, error) {
+	v, err := r.required(column)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, r.errorf("error parsing %s: %v", column, err)
+	}
+	return f, nil
+}
+
+// time parses an optional time of the form H:MM:SS.
+func (r gtfsRecord) time(column string) (Time, error) {
+	v := r.get(column)
+	if v == "" {
+		return NoTime, nil
+	}
+
+	parts := strings.Split(v, ":")
+	if len(parts) != 3 {
+		return 0, r.errorf("expected %s of format 'HH:MM:SS' but got %q", column, v)
+	}
+
+	var t int
+	for _, p := range parts {
+		x, err := strconv.Atoi(p)
+		if err != nil || x < 0 {
+			return 0, r.errorf("expected %s of format 'HH:MM:SS' but got %q", column, v)
+		}
+		t = 60*t + x
+	}
+
+	return Time(t), nil
+}
+
+// date parses a required date of the form YYYYMMDD.
+func (r gtfsRecord) date(column string) (time.Time, error) {
+	v, err := r.required(column)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	d, err := time.Parse("20060102", v)
+	if err != nil {
+		return time.Time{}, r.errorf("error parsing %s: %v", column, err)
+	}
+	return d, nil
+}
+
+func (r gtfsRecord) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s line %d: %s", r.file, r.line, fmt.Sprintf(format, args...))
+}
+
+func readGTFSFile(fsys fs.FS, name string, handle func(gtfsRecord) error) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cr := csv.NewReader(f)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s is empty", name)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", name, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		if i == 0 {
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+		columns[strings.TrimSpace(column)] = i
+	}
+
+	for {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", name, err)
+		}
+
+		if len(fields) == 1 && strings.TrimSpace(fields[0]) == "" {
+			continue
+		}
+
+		// The line of the record's start, which differs from a count of the
+		// records if quoted fields contain newlines.
+		line, _ := cr.FieldPos(0)
+		err = handle(gtfsRecord{file: name, line: line, columns: columns, fields: fields})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func parseStop(tt *Timetable, r gtfsRecord) error {
+	id, err := r.required("stop_id")
+	if err != nil {
+		return err
+	}
+
+	locationType, err := r.int("location_type")
+	if err != nil {
+		return err
+	}
+
+	stop := &Stop{
+		ID:            id,
+		Name:          r.get("stop_name"),
+		LocationType:  locationType,
+		ParentStation: r.get("parent_station"),
+	}
+
+	// Generic nodes and boarding areas may omit their position.
+	if locationType <= 2 {
+		if stop.Coordinate.Lat, err = r.float("stop_lat"); err != nil {
+			return err
+		}
+		if stop.Coordinate.Lon, err = r.float("stop_lon"); err != nil {
+			return err
+		}
+	}
+
+	tt.Stops[id] = stop
+	return nil
+}
+
+func parseRoute(tt *Timetable, r gtfsRecord) error {
+	id, err := r.required("route_id")
+	if err != nil {
+		return err
+	}
+
+	routeType, err := r.int("route_type")
+	if err != nil {
+		return err
+	}
+
+	tt.Routes[id] = &Route{
+		ID:        id,
+		AgencyID:  r.get("agency_id"),
+		ShortName: r.get("route_short_name"),
+		LongName:  r.get("route_long_name"),
+		Type:      routeType,
+	}
+	return nil
+}
+
+func parseTrip(tt *Timetable, r gtfsRecord) error {
+	id, err := r.required("trip_id")
+	if err != nil {
+		return err
+	}
+
+	routeID, err := r.required("route_id")
+	if err != nil {
+		return err
+	}
+	if _, ok := tt.Routes[routeID]; !ok {
+		return r.errorf("unknown route %q", routeID)
+	}
+
+	serviceID, err := r.required("service_id")
+	if err != nil {
+		return err
+	}
+
+	direction, err := r.int("direction_id")
+	if err != nil {
+		return err
+	}
+
+	tt.Trips[id] = &Trip{
+		ID:          id,
+		RouteID:     routeID,
+		ServiceID:   serviceID,
+		Headsign:    r.get("trip_headsign"),
+		DirectionID: direction,
+	}
+	return nil
+}
+
+func parseStopTime(tt *Timetable, r gtfsRecord) error {
+	tripID, err := r.required("trip_id")
+	if err != nil {
+		return err
+	}
+	trip, ok := tt.Trips[tripID]
+	if !ok {
+		return r.errorf("unknown trip %q", tripID)
+	}
+
+	stopID, err := r.required("stop_id")
+	if err != nil {
+		return err
+	}
+	if _, ok := tt.Stops[stopID]; !ok {
+		return r.errorf("unknown stop %q", stopID)
+	}
+
+	st := StopTime{StopID: stopID}
+
+	if st.Arrival, err = r.time("arrival_time"); err != nil {
+		return err
+	}
+	if st.Departure, err = r.time("departure_time"); err != nil {
+		return err
+	}
+	if st.Sequence, err = r.requiredInt("stop_sequence"); err != nil {
+		return err
+	}
+
+	trip.StopTimes = append(trip.StopTimes, st)
+	return nil
+}
+
+var gtfsWeekdays = []struct {
+	column  string
+	weekday time.Weekday
+}{
+	{"monday", time.Monday},
+	{"tuesday", time.Tuesday},
+	{"wednesday", time.Wednesday},
+	{"thursday", time.Thursday},
+	{"friday", time.Friday},
+	{"saturday", time.Saturday},
+	{"sunday", time.Sunday},
+}
+
+func parseCalendar(tt *Timetable, r gtfsRecord) error {
+	serviceID, err := r.required("service_id")
+	if err != nil {
+		return err
+	}
+
+	c := Calendar{ServiceID: serviceID}
+
+	for _, wd := range gtfsWeekdays {
+		active, err := r.requiredInt(wd.column)
+		if err != nil {
+			return err
+		}
+		c.Weekdays[wd.weekday] = active == 1
+	}
+
+	if c.Start, err = r.date("start_date"); err != nil {
+		return err
+	}
+	if c.End, err = r.date("end_date"); err != nil {
+		return err
+	}
+
+	tt.Calendars[serviceID] = c
+	return nil
+}
+
+func parseCalendarDate(tt *Timetable, r gtfsRecord) error {
+	serviceID, err := r.required("service_id")
+	if err != nil {
+		return err
+	}
+
+	date, err := r.date("date")
+	if err != nil {
+		return err
+	}
+
+	exception, err := r.int("exception_type")
+	if err != nil {
+		return err
+	}
+	if ExceptionType(exception) != ServiceAdded && ExceptionType(exception) != ServiceRemoved {
+		return r.errorf("invalid exception_type %d", exception)
+	}
+
+	tt.CalendarDates[serviceID] = append(tt.CalendarDates[serviceID], CalendarDate{
+		ServiceID: serviceID,
+		Date:      date,
+		Exception: ExceptionType(exception),
+	})
+	return nil
+}
+
+func parseTransfer(tt *Timetable, r gtfsRecord) error {
+	from, err := r.required("from_stop_id")
+	if err != nil {
+		return err
+	}
+
+	to, err := r.required("to_stop_id")
+	if err != nil {
+		return err
+	}
+
+	t := Transfer{FromStopID: from, ToStopID: to}
+
+	if t.Type, err = r.int("transfer_type"); err != nil {
+		return err
+	}
+	if t.MinTransferTime, err = r.int("min_transfer_time"); err != nil {
+		return err
+	}
+
+	tt.Transfers = append(tt.Transfers, t)
+	return nil
+}
@@ -0,0 +1,158 @@
+package transit_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"route-planning/transit"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var testFeed = map[string]string{
+	"stops.txt": "\ufeffstop_id,stop_name,stop_lat,stop_lon,location_type,parent_station\n" +
+		"S1,Hauptbahnhof,48.140,11.558,1,\n" +
+		"S1a,Hauptbahnhof Gleis 1,48.141,11.559,0,S1\n" +
+		"S2,Marienplatz,48.137,11.575,0,\n",
+	"routes.txt": "route_id,agency_id,route_short_name,route_long_name,route_type\n" +
+		"R1,MVG,S1,Freising,2\n",
+	"trips.txt": "route_id,service_id,trip_id,trip_headsign,direction_id\n" +
+		"R1,WD,T1,Marienplatz,0\n" +
+		"R1,WD,T2,Marienplatz,0\n",
+	"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+		"T1,08:05:00,08:05:30,S2,2\n" +
+		"T1,08:00:00,08:00:00,S1a,1\n" +
+		"T2,24:10:00,24:10:00,S1a,1\n" +
+		"T2,,,S2,2\n",
+	"calendar.txt": "service_id,monday,tuesday,wednesday,thursday,friday,saturday,sunday,start_date,end_date\n" +
+		"WD,1,1,1,1,1,0,0,20240101,20241231\n",
+	"calendar_dates.txt": "service_id,date,exception_type\n" +
+		"WD,20241003,2\n" +
+		"WD,20241005,1\n",
+	"transfers.txt": "from_stop_id,to_stop_id,transfer_type,min_transfer_time\n" +
+		"S1a,S2,2,300\n",
+}
+
+func testFS() fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, data := range testFeed {
+		fsys[name] = &fstest.MapFile{Data: []byte(data)}
+	}
+	return fsys
+}
+
+func TestLoadGTFSFS(t *testing.T) {
+	tt, err := transit.LoadGTFSFS(testFS())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tt.Stops) != 3 || len(tt.Routes) != 1 || len(tt.Trips) != 2 || len(tt.Transfers) != 1 {
+		t.Errorf("unexpected timetable size: %d stops, %d routes, %d trips, %d transfers",
+			len(tt.Stops), len(tt.Routes), len(tt.Trips), len(tt.Transfers))
+	}
+
+	if s := tt.Stops["S1a"]; s.ParentStation != "S1" || s.Coordinate.Lat != 48.141 {
+		t.Errorf("unexpected stop %+v", s)
+	}
+
+	expected := []transit.StopTime{
+		{StopID: "S1a", Arrival: 8 * 3600, Departure: 8 * 3600, Sequence: 1},
+		{StopID: "S2", Arrival: 8*3600 + 5*60, Departure: 8*3600 + 5*60 + 30, Sequence: 2},
+	}
+	if got := tt.Trips["T1"].StopTimes; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected stop times %v, got %v", expected, got)
+	}
+
+	if st := tt.Trips["T2"].StopTimes; st[0].Arrival.String() != "24:10:00" || st[1].Arrival != transit.NoTime {
+		t.Errorf("unexpected stop times %v", st)
+	}
+
+	active := map[string]bool{
+		"2024-10-02": true,  // Wednesday
+		"2024-10-03": false, // holiday
+		"2024-10-05": true,  // added Saturday
+		"2024-10-06": false, // Sunday
+		"2025-01-01": false, // after end date
+	}
+	for day, expected := range active {
+		date, _ := time.Parse("2006-01-02", day)
+		if got := tt.ServiceActive("WD", date); got != expected {
+			t.Errorf("service active on %s: expected %v, got %v", day, expected, got)
+		}
+	}
+}
+
+func TestLoadGTFSZip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "feed.zip")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zw := zip.NewWriter(f)
+	for name, data := range testFeed {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	tt, err := transit.LoadGTFS(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tt.Trips) != 2 {
+		t.Errorf("expected 2 trips, got %d", len(tt.Trips))
+	}
+}
+
+func TestLoadGTFSFSUnknownStop(t *testing.T) {
+	fsys := testFS()
+	fsys["stop_times.txt"] = &fstest.MapFile{Data: []byte("trip_id,arrival_time,departure_time,stop_id,stop_sequence\nT1,08:00:00,08:00:00,S9,1\n")}
+
+	_, err := transit.LoadGTFSFS(fsys)
+	if err == nil {
+		t.Fatalf("expected error for unknown stop")
+	}
+}
+
+func TestLoadGTFSFSMissingRequiredFields(t *testing.T) {
+	tests := map[string]string{
+		"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id\nT1,08:00:00,08:00:00,S1a\n",
+		"calendar.txt":   "service_id,start_date,end_date\nWD,20240101,20241231\n",
+	}
+
+	for file, data := range tests {
+		fsys := testFS()
+		fsys[file] = &fstest.MapFile{Data: []byte(data)}
+
+		_, err := transit.LoadGTFSFS(fsys)
+		if err == nil || !strings.Contains(err.Error(), "missing required field") {
+			t.Errorf("%s: expected missing required field error, got %v", file, err)
+		}
+	}
+}
+
+func TestLoadGTFSFSErrorLine(t *testing.T) {
+	fsys := testFS()
+	fsys["stops.txt"] = &fstest.MapFile{Data: []byte("stop_id,stop_name,stop_lat,stop_lon\n" +
+		"S1,\"Haupt\nbahnhof\",48.140,11.558\n" +
+		"S2,Marienplatz,north,11.575\n")}
+
+	_, err := transit.LoadGTFSFS(fsys)
+	if err == nil || !strings.Contains(err.Error(), "stops.txt line 4") {
+		t.Errorf("expected error in stops.txt line 4, got %v", err)
+	}
+}
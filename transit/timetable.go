@@ -0,0 +1,115 @@
+package transit
+
+import (
+	"fmt"
+	"route-planning/graph"
+	"time"
+)
+
+// Time is a time of day in seconds after midnight of the service day. Times
+// after midnight of the next day exceed 24h.
+type Time int
+
+// NoTime marks stop times without arrival or departure time.
+const NoTime Time = -1
+
+func (t Time) String() string {
+	if t == NoTime {
+		return "--:--:--"
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", t/3600, t/60%60, t%60)
+}
+
+type Stop struct {
+	ID         string
+	Name       string
+	Coordinate graph.Coordinate
+	// LocationType is 0 for stops and platforms and 1 for stations.
+	LocationType  int
+	ParentStation string
+}
+
+type Route struct {
+	ID        string
+	AgencyID  string
+	ShortName string
+	LongName  string
+	// Type is the GTFS route type, e.g. 0 for trams and 3 for buses.
+	Type int
+}
+
+type Trip struct {
+	ID          string
+	RouteID     string
+	ServiceID   string
+	Headsign    string
+	DirectionID int
+	// StopTimes are sorted by their sequence.
+	StopTimes []StopTime
+}
+
+type StopTime struct {
+	StopID    string
+	Arrival   Time
+	Departure Time
+	Sequence  int
+}
+
+// Calendar describes on which weekdays between Start and End a service runs.
+type Calendar struct {
+	ServiceID string
+	// Weekdays is indexed by time.Weekday.
+	Weekdays [7]bool
+	Start    time.Time
+	End      time.Time
+}
+
+type ExceptionType int
+
+const (
+	ServiceAdded   ExceptionType = 1
+	ServiceRemoved ExceptionType = 2
+)
+
+type CalendarDate struct {
+	ServiceID string
+	Date      time.Time
+	Exception ExceptionType
+}
+
+type Transfer struct {
+	FromStopID string
+	ToStopID   string
+	// Type is the GTFS transfer type, 2 requires MinTransferTime.
+	Type            int
+	MinTransferTime int
+}
+
+// Timetable is a public transit timetable. Stops, routes and trips are
+// indexed by their ID, calendars and calendar dates by service ID.
+type Timetable struct {
+	Stops         map[string]*Stop
+	Routes        map[string]*Route
+	Trips         map[string]*Trip
+	Calendars     map[string]Calendar
+	CalendarDates map[string][]CalendarDate
+	Transfers     []Transfer
+}
+
+// ServiceActive returns whether the service runs on the day of date.
+func (tt *Timetable) ServiceActive(serviceID string, date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	for _, cd := range tt.CalendarDates[serviceID] {
+		if cd.Date.Equal(day) {
+			return cd.Exception == ServiceAdded
+		}
+	}
+
+	c, ok := tt.Calendars[serviceID]
+	if !ok {
+		return false
+	}
+
+	return !day.Before(c.Start) && !day.After(c.End) && c.Weekdays[day.Weekday()]
+}